@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/qoinlyid/qore"
+	"github.com/qoinlyid/qore/internal/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// OpenAPI command.
+	openapiCmd = &cobra.Command{
+		Use:   "openapi",
+		Short: "Export OpenAPI document of the project",
+		Long: `Export OpenAPI 3.1 document of the project.
+The project main package is run with QORE_OPENAPI_EXPORT env, so the application
+writes the document of its registered HTTP(s) routes then exits without being started.
+The document is written as YAML when the output file extension is .yaml or .yml.`,
+		RunE: openapiRunE,
+	}
+)
+
+var (
+	openapiOutput string
+	openapiMain   string
+)
+
+func init() {
+	openapiCmd.Flags().StringVarP(&openapiOutput, "output", "o", "openapi.json", "--output to set OpenAPI document file")
+	openapiCmd.Flags().StringVarP(&openapiMain, "main", "m", ".", "--main to set project main package")
+
+	// Add to root.
+	rootCmd.AddCommand(openapiCmd)
+}
+
+// openapiRunE is runner for OpenAPI command.
+func openapiRunE(cmd *cobra.Command, args []string) error {
+	output, err := filepath.Abs(openapiOutput)
+	if err != nil {
+		return fmt.Errorf("invalid output file: %w", err)
+	}
+	verboseMessage(fmt.Sprintf("Main package: %s", openapiMain))
+
+	_, err = command.NewCommandWizard(command.WizardConfig{
+		Title:         "📘 OpenAPI exporter",
+		Description:   "Export OpenAPI document of your application project",
+		ShowProgress:  true,
+		ResultColor:   command.ColorCyan,
+		ShowFinish:    true,
+		FinishMessage: fmt.Sprintf("✨ OpenAPI document is written into %s", output),
+	}).
+		AddProcess("openapi_export", "📦 Exporting OpenAPI document...",
+			func(results command.WizardResults, progress func(string)) (any, error) {
+				// Remove the previous document, so the stale file is not reported as written.
+				if err := os.Remove(output); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return nil, fmt.Errorf("failed to remove previous OpenAPI document: %w", err)
+				}
+
+				progress("🚧 Running project main package...")
+				var stderr bytes.Buffer
+				run := exec.Command("go", "run", openapiMain)
+				run.Env = append(os.Environ(), fmt.Sprintf("%s=%s", qore.OPENAPI_EXPORT_KEY, output))
+				run.Stderr = &stderr
+				if err := run.Run(); err != nil {
+					return nil, fmt.Errorf("failed to run main package: %w: %s", err, strings.TrimSpace(stderr.String()))
+				}
+				if _, err := os.Stat(output); err != nil {
+					return nil, fmt.Errorf("OpenAPI document is not written: %w", err)
+				}
+				return "Success", nil
+			},
+		).
+		Run()
+	if err != nil {
+		return fmt.Errorf("failed export OpenAPI document: %w", err)
+	}
+	return nil
+}
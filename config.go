@@ -15,6 +15,7 @@ import (
 type Config struct {
 	// App config.
 	AppName          string `json:"APP_NAME" mapstructure:"APP_NAME"`
+	AppVersion       string `json:"APP_VERSION" mapstructure:"APP_VERSION"`
 	AppProduction    bool   `json:"APP_PRODUCTION" mapstructure:"APP_PRODUCTION"`
 	AppContainerized bool   `json:"APP_CONTAINERIZED" mapstructure:"APP_CONTAINERIZED"`
 	ShutdownTimeout  int    `json:"APP_SHUTDOWN_TIMEOUT" mapstructure:"APP_SHUTDOWN_TIMEOUT"`
@@ -29,11 +30,65 @@ type Config struct {
 	HTTPAutoTLS  bool   `json:"HTTP_AUTO_TLS" mapstructure:"HTTP_AUTO_TLS"`
 	HTTPCertPath string `json:"HTTP_CERT_PATH" mapstructure:"HTTP_CERT_PATH"`
 	HTTPKeyPath  string `json:"HTTP_KEY_PATH" mapstructure:"HTTP_KEY_PATH"`
+
+	// HTTP validator default locale, used when the `Accept-Language` request header is not supported.
+	HTTPValidatorLocale string `json:"HTTP_VALIDATOR_LOCALE" mapstructure:"HTTP_VALIDATOR_LOCALE"`
+
+	// HTTP API response config, `PROBLEM` writes the error response as RFC 9457 problem details
+	// with the problem type URI of `HTTP_PROBLEM_TYPE_BASE`.
+	HTTPApiResponse     string `json:"HTTP_API_RESPONSE" mapstructure:"HTTP_API_RESPONSE"`
+	HTTPProblemTypeBase string `json:"HTTP_PROBLEM_TYPE_BASE" mapstructure:"HTTP_PROBLEM_TYPE_BASE"`
+
+	// HTTP admin server config, admin server is disabled when the port is zero.
+	// The TLS of the admin server is not inherited from the HTTP server, it serves plain HTTP
+	// unless its own auto TLS or certificate file is set.
+	HTTPAdminPort     int    `json:"HTTP_ADMIN_PORT" mapstructure:"HTTP_ADMIN_PORT"`
+	HTTPAdminAutoTLS  bool   `json:"HTTP_ADMIN_AUTO_TLS" mapstructure:"HTTP_ADMIN_AUTO_TLS"`
+	HTTPAdminCertPath string `json:"HTTP_ADMIN_CERT_PATH" mapstructure:"HTTP_ADMIN_CERT_PATH"`
+	HTTPAdminKeyPath  string `json:"HTTP_ADMIN_KEY_PATH" mapstructure:"HTTP_ADMIN_KEY_PATH"`
+
+	// gRPC Server config.
+	GRPCPort int `json:"GRPC_PORT" mapstructure:"GRPC_PORT"`
+
+	// HTTP health probe config.
+	HTTPHealthEnabled bool   `json:"HTTP_HEALTH_ENABLED" mapstructure:"HTTP_HEALTH_ENABLED"`
+	HTTPHealthServer  string `json:"HTTP_HEALTH_SERVER" mapstructure:"HTTP_HEALTH_SERVER"`
+	HTTPHealthPath    string `json:"HTTP_HEALTH_PATH" mapstructure:"HTTP_HEALTH_PATH"`
+	HTTPReadyPath     string `json:"HTTP_READY_PATH" mapstructure:"HTTP_READY_PATH"`
+	HTTPLivePath      string `json:"HTTP_LIVE_PATH" mapstructure:"HTTP_LIVE_PATH"`
+	HTTPHealthTimeout int    `json:"HTTP_HEALTH_TIMEOUT" mapstructure:"HTTP_HEALTH_TIMEOUT"`
+
+	// HTTP OpenAPI document config, the document is not served when the path is empty.
+	HTTPOpenAPIPath string `json:"HTTP_OPENAPI_PATH" mapstructure:"HTTP_OPENAPI_PATH"`
+
+	// HTTP metrics config, Prometheus metrics is served on the `HTTP_METRICS_SERVER` server,
+	// empty server is the default server.
+	HTTPMetricsEnabled bool   `json:"HTTP_METRICS_ENABLED" mapstructure:"HTTP_METRICS_ENABLED"`
+	HTTPMetricsServer  string `json:"HTTP_METRICS_SERVER" mapstructure:"HTTP_METRICS_SERVER"`
+	HTTPMetricsPath    string `json:"HTTP_METRICS_PATH" mapstructure:"HTTP_METRICS_PATH"`
+
+	// Tracing config, the span is recorded & exported via OTLP/HTTP only when the endpoint is set,
+	// e.g. `http://localhost:4318/v1/traces`. The sample ratio is 0 to 1, 0 disables the root span sampling.
+	TracingEndpoint    string  `json:"TRACING_ENDPOINT" mapstructure:"TRACING_ENDPOINT"`
+	TracingSampleRatio float64 `json:"TRACING_SAMPLE_RATIO" mapstructure:"TRACING_SAMPLE_RATIO"`
+
+	// Dependency config.
+	DependencyPolicy       DependencyPolicy `json:"DEPENDENCY_POLICY" mapstructure:"DEPENDENCY_POLICY"`
+	DependencyRetryMax     int              `json:"DEPENDENCY_RETRY_MAX" mapstructure:"DEPENDENCY_RETRY_MAX"`
+	DependencyRetryBackoff int              `json:"DEPENDENCY_RETRY_BACKOFF" mapstructure:"DEPENDENCY_RETRY_BACKOFF"`
+	DependencyOpenTimeout  int              `json:"DEPENDENCY_OPEN_TIMEOUT" mapstructure:"DEPENDENCY_OPEN_TIMEOUT"`
+	DependencyCloseTimeout int              `json:"DEPENDENCY_CLOSE_TIMEOUT" mapstructure:"DEPENDENCY_CLOSE_TIMEOUT"`
+
+	// Dependency monitor config.
+	DependencyMonitorInterval      int `json:"DEPENDENCY_MONITOR_INTERVAL" mapstructure:"DEPENDENCY_MONITOR_INTERVAL"`
+	DependencyMonitorFlapThreshold int `json:"DEPENDENCY_MONITOR_FLAP_THRESHOLD" mapstructure:"DEPENDENCY_MONITOR_FLAP_THRESHOLD"`
+	DependencyMonitorFlapWindow    int `json:"DEPENDENCY_MONITOR_FLAP_WINDOW" mapstructure:"DEPENDENCY_MONITOR_FLAP_WINDOW"`
 }
 
 var defaultConfig = &Config{
 	// App.
 	AppName:          "qore-app",
+	AppVersion:       "0.1.0",
 	AppContainerized: true,
 	ShutdownTimeout:  30,
 
@@ -42,7 +97,34 @@ var defaultConfig = &Config{
 	LogShowSource: true,
 
 	// HTTP.
-	HTTPPort: 3100,
+	HTTPPort:          3100,
+	HTTPHealthPath:    "/healthz",
+	HTTPReadyPath:     "/readyz",
+	HTTPLivePath:      "/livez",
+	HTTPHealthTimeout: 3000,
+
+	// HTTP validator.
+	HTTPValidatorLocale: HTTP_VALIDATOR_LOCALE_EN,
+
+	// HTTP API response.
+	HTTPApiResponse: HTTP_API_RESPONSE_DEFAULT,
+
+	// HTTP metrics.
+	HTTPMetricsPath: "/metrics",
+
+	// Tracing.
+	TracingSampleRatio: 1,
+
+	// Dependency.
+	DependencyPolicy:       DEPENDENCY_POLICY_DEGRADE,
+	DependencyRetryMax:     3,
+	DependencyRetryBackoff: 500,
+	DependencyOpenTimeout:  30,
+
+	// Dependency monitor.
+	DependencyMonitorInterval:      15,
+	DependencyMonitorFlapThreshold: 4,
+	DependencyMonitorFlapWindow:    300,
 }
 
 func loadConfig() *Config {
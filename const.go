@@ -3,6 +3,19 @@ package qore
 // Config used key.
 const CONFIG_USED_KEY = "USED_CONFIG"
 
+// OpenAPI export environment key, when it is set `App.Start` writes the OpenAPI document
+// into the file of the env value then exits without running the application.
+const OPENAPI_EXPORT_KEY = "QORE_OPENAPI_EXPORT"
+
+// OpenTelemetry tracer name of the qore instrumentation.
+const TRACER_NAME = "github.com/qoinlyid/qore"
+
+// gRPC metadata key for trace id.
+const GRPC_METADATA_TRACE_ID = "x-trace-id"
+
+// Maximum length of the trace id that is accepted from the incoming request.
+const TRACE_ID_MAX_LENGTH = 128
+
 // Enum of logging level.
 const (
 	LOG_DEBUG LogLevel = "DEBUG"
@@ -10,3 +23,46 @@ const (
 	LOG_WARN  LogLevel = "WARN"
 	LOG_ERROR LogLevel = "ERROR"
 )
+
+// Enum of dependency open policy.
+const (
+	// DEPENDENCY_POLICY_ABORT aborts the application startup when dependency failed to open.
+	DEPENDENCY_POLICY_ABORT DependencyPolicy = "ABORT"
+	// DEPENDENCY_POLICY_RETRY retries to open dependency with backoff, then aborts the application startup.
+	DEPENDENCY_POLICY_RETRY DependencyPolicy = "RETRY"
+	// DEPENDENCY_POLICY_DEGRADE keeps the application startup without the failed dependency, it is the default policy.
+	// The failed dependency is reopened by the dependency monitor.
+	DEPENDENCY_POLICY_DEGRADE DependencyPolicy = "DEGRADE"
+)
+
+// Enum of dependency health state.
+const (
+	DEPENDENCY_STATE_UNKNOWN   DependencyState = "UNKNOWN"
+	DEPENDENCY_STATE_HEALTHY   DependencyState = "HEALTHY"
+	DEPENDENCY_STATE_UNHEALTHY DependencyState = "UNHEALTHY"
+	DEPENDENCY_STATE_DEGRADED  DependencyState = "DEGRADED"
+)
+
+// Enum of background worker restart policy.
+const (
+	WORKER_RESTART_NEVER      WorkerRestartPolicy = "NEVER"
+	WORKER_RESTART_ON_FAILURE WorkerRestartPolicy = "ON_FAILURE"
+	WORKER_RESTART_ALWAYS     WorkerRestartPolicy = "ALWAYS"
+)
+
+// Enum of background worker state.
+const (
+	WORKER_STATE_IDLE    WorkerState = "IDLE"
+	WORKER_STATE_RUNNING WorkerState = "RUNNING"
+	WORKER_STATE_BACKOFF WorkerState = "BACKOFF"
+	WORKER_STATE_STOPPED WorkerState = "STOPPED"
+	WORKER_STATE_FAILED  WorkerState = "FAILED"
+)
+
+// Enum of scheduled task missed-run policy.
+const (
+	// SCHEDULE_MISSED_SKIP skips the run that is missed due to overlap or late wake-up.
+	SCHEDULE_MISSED_SKIP ScheduleMissedPolicy = "SKIP"
+	// SCHEDULE_MISSED_RUN_ONCE coalesces the missed runs into a single run as soon as possible.
+	SCHEDULE_MISSED_RUN_ONCE ScheduleMissedPolicy = "RUN_ONCE"
+)
@@ -9,7 +9,8 @@ const (
 	CTX_TRACE_ID = contextKey("traceId")
 )
 
-// ContextFromHttp wraps HTTP(s) request context and return `context.Context`.
+// ContextFromHttp wraps HTTP(s) request context and return `context.Context`,
+// the context carries the OpenTelemetry span of the request that is created by `httpmw.TraceID`.
 func ContextFromHttp(c HttpContext) (ctx context.Context) {
 	ctx = c.Request().Context()
 
@@ -20,3 +20,31 @@ type Dependency interface {
 	Open() error
 	Close() error
 }
+
+// DependencyDependsOn is optional interface for `Dependency` that declares other dependencies
+// (by their `Dependency.Name()`) that must be opened before it and closed after it.
+type DependencyDependsOn interface {
+	DependsOn() []string
+}
+
+// DependencyOpenPolicy is optional interface for `Dependency` that overrides
+// the app-wide `Config.DependencyPolicy` when the dependency failed to open.
+type DependencyOpenPolicy interface {
+	OpenPolicy() DependencyPolicy
+}
+
+// DependencyCritical is optional interface for `Dependency` that marks whether the dependency is critical.
+// The unhealthy critical dependency makes the health & readiness probe return 503, every dependency
+// is critical unless it implements the interface and returns false.
+type DependencyCritical interface {
+	Critical() bool
+}
+
+// DependencyContext is optional interface for `Dependency` that opens and closes with context.
+// When implemented, it is used instead of `Dependency.Open` and `Dependency.Close`,
+// the context deadline is derived from `Config.DependencyOpenTimeout` and the shutdown deadline of `Config.DependencyCloseTimeout`
+// that is shared by every dependency.
+type DependencyContext interface {
+	OpenContext(ctx context.Context) error
+	CloseContext(ctx context.Context) error
+}
@@ -0,0 +1,115 @@
+package qore
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrDependencyCycle       = errors.New("dependency cycle detected")
+	ErrDependencyUnknown     = errors.New("unknown dependency")
+	ErrDependencyDuplicated  = errors.New("duplicated dependency name")
+	ErrDependencyUnavailable = errors.New("required dependency is unavailable")
+)
+
+// dependencyGraph builds ordered batches of dependency from given registry.
+// Every batch can be opened in parallel, while batches must be opened serially.
+// A batch contains dependencies in the same topological level and the same priority,
+// so `Dependency.Priority()` remains as a tiebreaker inside a level.
+func dependencyGraph(dependencies []Dependency) ([][]Dependency, error) {
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+
+	// Index dependency by name.
+	nodes := make(map[string]Dependency, len(dependencies))
+	for _, dependency := range dependencies {
+		name := dependency.Name()
+		if _, exists := nodes[name]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyDuplicated, name)
+		}
+		nodes[name] = dependency
+	}
+
+	// Build edges & in-degree.
+	inDegree := make(map[string]int, len(nodes))
+	dependents := make(map[string][]string, len(nodes))
+	for name, dependency := range nodes {
+		inDegree[name] += 0
+		d, ok := dependency.(DependencyDependsOn)
+		if !ok {
+			continue
+		}
+		seen := make(map[string]struct{})
+		for _, parent := range d.DependsOn() {
+			if _, dup := seen[parent]; dup {
+				continue
+			}
+			seen[parent] = struct{}{}
+			if _, exists := nodes[parent]; !exists {
+				return nil, fmt.Errorf("%w: %s depends on %s", ErrDependencyUnknown, name, parent)
+			}
+			inDegree[name]++
+			dependents[parent] = append(dependents[parent], name)
+		}
+	}
+
+	// Kahn's algorithm per level.
+	var (
+		batches [][]Dependency
+		visited int
+		level   []string
+	)
+	for name, degree := range inDegree {
+		if degree == 0 {
+			level = append(level, name)
+		}
+	}
+	for len(level) > 0 {
+		// Split level into priority batches.
+		sort.Slice(level, func(i, j int) bool {
+			pi, pj := nodes[level[i]].Priority(), nodes[level[j]].Priority()
+			if pi != pj {
+				return pi < pj
+			}
+			return level[i] < level[j]
+		})
+		for i := 0; i < len(level); {
+			priority := nodes[level[i]].Priority()
+			batch := make([]Dependency, 0)
+			for ; i < len(level) && nodes[level[i]].Priority() == priority; i++ {
+				batch = append(batch, nodes[level[i]])
+			}
+			batches = append(batches, batch)
+		}
+		visited += len(level)
+
+		// Next level.
+		var next []string
+		for _, name := range level {
+			for _, child := range dependents[name] {
+				inDegree[child]--
+				if inDegree[child] == 0 {
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+
+	// Remaining node with in-degree means there is a cycle.
+	if visited != len(nodes) {
+		var cyclic []string
+		for name, degree := range inDegree {
+			if degree > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		slices.Sort(cyclic)
+		return nil, fmt.Errorf("%w between: %s", ErrDependencyCycle, strings.Join(cyclic, ", "))
+	}
+	return batches, nil
+}
@@ -0,0 +1,98 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type dependencyTest struct {
+	name      string
+	priority  int
+	dependsOn []string
+}
+
+func (d *dependencyTest) Name() string                                     { return d.name }
+func (d *dependencyTest) Priority() int                                    { return d.priority }
+func (d *dependencyTest) HealthCheck(ctx context.Context) *DependencyStats { return nil }
+func (d *dependencyTest) Open() error                                      { return nil }
+func (d *dependencyTest) Close() error                                     { return nil }
+func (d *dependencyTest) DependsOn() []string                              { return d.dependsOn }
+
+func dependencyBatchNames(batches [][]Dependency) [][]string {
+	names := make([][]string, 0, len(batches))
+	for _, batch := range batches {
+		x := make([]string, 0, len(batch))
+		for _, dependency := range batch {
+			x = append(x, dependency.Name())
+		}
+		names = append(names, x)
+	}
+	return names
+}
+
+func TestDependencyGraphOk(t *testing.T) {
+	batches, err := dependencyGraph([]Dependency{
+		&dependencyTest{name: "cache", priority: 1, dependsOn: []string{"config"}},
+		&dependencyTest{name: "db", priority: 1, dependsOn: []string{"config"}},
+		&dependencyTest{name: "config", priority: 5},
+		&dependencyTest{name: "logger", priority: 1},
+		&dependencyTest{name: "queue", priority: 1, dependsOn: []string{"db", "cache"}},
+		&dependencyTest{name: "mailer", priority: 2, dependsOn: []string{"config"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := dependencyBatchNames(batches)
+	want := [][]string{{"logger"}, {"config"}, {"cache", "db"}, {"mailer"}, {"queue"}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		}
+	}
+}
+
+func TestDependencyGraphPriorityOk(t *testing.T) {
+	batches, err := dependencyGraph([]Dependency{
+		&dependencyTest{name: "b", priority: 20},
+		&dependencyTest{name: "a", priority: 10},
+		&dependencyTest{name: "c", priority: 20},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := dependencyBatchNames(batches)
+	if len(got) != 2 || got[0][0] != "a" || len(got[1]) != 2 {
+		t.Fatalf("unexpected batches: %v", got)
+	}
+}
+
+func TestDependencyGraphCycleErr(t *testing.T) {
+	_, err := dependencyGraph([]Dependency{
+		&dependencyTest{name: "a", dependsOn: []string{"c"}},
+		&dependencyTest{name: "b", dependsOn: []string{"a"}},
+		&dependencyTest{name: "c", dependsOn: []string{"b"}},
+		&dependencyTest{name: "d"},
+	})
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestDependencyGraphUnknownErr(t *testing.T) {
+	_, err := dependencyGraph([]Dependency{
+		&dependencyTest{name: "a", dependsOn: []string{"x"}},
+	})
+	if !errors.Is(err, ErrDependencyUnknown) {
+		t.Fatalf("expected unknown error, got %v", err)
+	}
+}
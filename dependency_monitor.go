@@ -0,0 +1,218 @@
+package qore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DependencyStateChangeFunc defines a function that is executed when dependency state is changed.
+type DependencyStateChangeFunc func(name string, old, new DependencyState)
+
+// DependencyStatus defines tracked status of a dependency by the monitor.
+type DependencyStatus struct {
+	State       DependencyState `json:"state" xml:"state"`
+	Since       time.Time       `json:"since" xml:"since"`
+	Uptime      time.Duration   `json:"uptime" xml:"uptime"`
+	Flapping    bool            `json:"flapping" xml:"flapping"`
+	Transitions int             `json:"transitions" xml:"transitions"`
+}
+
+type dependencyMonitorEntry struct {
+	state       DependencyState
+	since       time.Time
+	transitions []time.Time
+	flapping    bool
+}
+
+type dependencyMonitor struct {
+	mu       sync.RWMutex
+	entries  map[string]*dependencyMonitorEntry
+	handlers []DependencyStateChangeFunc
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// Open call of the degraded dependency that is still running, it is only accessed by the monitor goroutine.
+	pending map[string]*dependencyCall
+}
+
+// OnDependencyStateChange registers given function that is executed by the background
+// dependency monitor when the health state of a dependency is changed.
+func (app *App) OnDependencyStateChange(fn DependencyStateChangeFunc) {
+	if fn == nil {
+		return
+	}
+	app.dependencyMonitor.mu.Lock()
+	defer app.dependencyMonitor.mu.Unlock()
+	app.dependencyMonitor.handlers = append(app.dependencyMonitor.handlers, fn)
+}
+
+// DependencyStatus returns tracked status of given dependency name.
+// It returns status with `DEPENDENCY_STATE_UNKNOWN` state when the dependency has not been monitored.
+func (app *App) DependencyStatus(name string) DependencyStatus {
+	app.dependencyMonitor.mu.RLock()
+	defer app.dependencyMonitor.mu.RUnlock()
+	entry, ok := app.dependencyMonitor.entries[name]
+	if !ok {
+		return DependencyStatus{State: DEPENDENCY_STATE_UNKNOWN}
+	}
+	status := DependencyStatus{
+		State:       entry.state,
+		Since:       entry.since,
+		Flapping:    entry.flapping,
+		Transitions: len(entry.transitions),
+	}
+	if entry.state == DEPENDENCY_STATE_HEALTHY {
+		status.Uptime = time.Since(entry.since)
+	}
+	return status
+}
+
+// startDependencyMonitor runs the background dependency monitor.
+func (app *App) startDependencyMonitor() {
+	interval := time.Duration(app.Config.DependencyMonitorInterval) * time.Second
+	if interval <= 0 || len(app.dependencyRegistry) == 0 {
+		return
+	}
+	logger := app.Logger().Group("dependency.monitor")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor := &app.dependencyMonitor
+	monitor.mu.Lock()
+	monitor.entries = make(map[string]*dependencyMonitorEntry, len(app.dependencyRegistry))
+	monitor.pending = make(map[string]*dependencyCall)
+	monitor.cancel = cancel
+	monitor.done = make(chan struct{})
+	monitor.mu.Unlock()
+
+	go func() {
+		defer close(monitor.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			app.checkDependencyMonitor(ctx, logger)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Debug(fmt.Sprintf("dependency monitor running every %s", interval))
+}
+
+// stopDependencyMonitor stops the background dependency monitor and wait until it is done,
+// the open call of the degraded dependency that is still running is abandoned.
+func (app *App) stopDependencyMonitor() {
+	monitor := &app.dependencyMonitor
+	if monitor.cancel == nil {
+		return
+	}
+	monitor.cancel()
+	<-monitor.done
+	monitor.cancel = nil
+	for name, call := range monitor.pending {
+		for _, dependency := range app.dependencyRegistry {
+			if dependency.Name() == name {
+				call.abandon(app, dependency)
+			}
+		}
+	}
+	monitor.pending = nil
+}
+
+// dependencyMonitorChange defines a state change of the dependency.
+type dependencyMonitorChange struct {
+	name     string
+	old, new DependencyState
+}
+
+// checkDependencyMonitor reopens the degraded dependencies, checks the dependencies health
+// and notifies the state changes.
+func (app *App) checkDependencyMonitor(ctx context.Context, logger *logger) {
+	monitor := &app.dependencyMonitor
+	if monitor.pending == nil {
+		monitor.pending = make(map[string]*dependencyCall)
+	}
+	app.reopenDegradedDependencies(ctx, monitor.pending, logger)
+	report := app.HealthCheck(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	monitor.mu.Lock()
+	changes := app.recordDependencyMonitor(report, time.Now(), logger)
+	handlers := append([]DependencyStateChangeFunc(nil), monitor.handlers...)
+	monitor.mu.Unlock()
+
+	// Notify subscribers.
+	for _, c := range changes {
+		for _, handler := range handlers {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						logger.Error(fmt.Sprintf("dependency state change handler panic: %v", r))
+					}
+				}()
+				handler(c.name, c.old, c.new)
+			}()
+		}
+	}
+}
+
+// recordDependencyMonitor records the health report into the monitor entries and returns the state changes,
+// the flapping transitions are pruned on every check so the stable dependency stops flapping.
+// The caller must hold the monitor lock.
+func (app *App) recordDependencyMonitor(report *HealthReport, now time.Time, logger *logger) []dependencyMonitorChange {
+	window := time.Duration(app.Config.DependencyMonitorFlapWindow) * time.Second
+	threshold := app.Config.DependencyMonitorFlapThreshold
+
+	var changes []dependencyMonitorChange
+	monitor := &app.dependencyMonitor
+	for _, dependency := range report.Dependencies {
+		entry, ok := monitor.entries[dependency.Name]
+		if !ok {
+			entry = &dependencyMonitorEntry{state: DEPENDENCY_STATE_UNKNOWN, since: now}
+			monitor.entries[dependency.Name] = entry
+		}
+		changed := entry.state != dependency.State
+
+		// Track the transitions inside flapping window.
+		if changed && entry.state != DEPENDENCY_STATE_UNKNOWN {
+			entry.transitions = append(entry.transitions, now)
+		}
+		for len(entry.transitions) > 0 && now.Sub(entry.transitions[0]) > window {
+			entry.transitions = entry.transitions[1:]
+		}
+		flapping := threshold > 0 && len(entry.transitions) >= threshold
+		switch {
+		case flapping && !entry.flapping:
+			logger.Warn(
+				fmt.Sprintf("dependency %s is flapping", dependency.Name),
+				slog.Int("transitions", len(entry.transitions)),
+				slog.String("window", window.String()),
+			)
+		case !flapping && entry.flapping:
+			logger.Info(fmt.Sprintf("dependency %s is no longer flapping", dependency.Name))
+		}
+		entry.flapping = flapping
+		if !changed {
+			continue
+		}
+
+		changes = append(changes, dependencyMonitorChange{name: dependency.Name, old: entry.state, new: dependency.State})
+		entry.state = dependency.State
+		entry.since = now
+
+		// Log the transition.
+		msg := fmt.Sprintf("dependency %s state changed from %s to %s", dependency.Name, changes[len(changes)-1].old, dependency.State)
+		if dependency.State == DEPENDENCY_STATE_HEALTHY {
+			logger.Info(msg)
+		} else {
+			logger.Warn(msg, slog.String("error", dependency.Error))
+		}
+	}
+	return changes
+}
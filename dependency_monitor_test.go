@@ -0,0 +1,228 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func dependencyMonitorTestReport(state DependencyState) *HealthReport {
+	return &HealthReport{Dependencies: []DependencyHealthReport{{Name: "db", State: state}}}
+}
+
+func TestDependencyMonitorFlappingOk(t *testing.T) {
+	app := &App{Config: &Config{DependencyMonitorFlapThreshold: 3, DependencyMonitorFlapWindow: 60}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	app.dependencyMonitor.entries = make(map[string]*dependencyMonitorEntry)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	states := []DependencyState{
+		DEPENDENCY_STATE_HEALTHY, DEPENDENCY_STATE_UNHEALTHY, DEPENDENCY_STATE_HEALTHY, DEPENDENCY_STATE_UNHEALTHY,
+	}
+	for i, state := range states {
+		changes := app.recordDependencyMonitor(dependencyMonitorTestReport(state), now.Add(time.Duration(i)*time.Second), app.logger)
+		if len(changes) != 1 || changes[0].new != state {
+			t.Fatalf("check %d expected change to %s, got %+v", i, state, changes)
+		}
+	}
+	if status := app.DependencyStatus("db"); !status.Flapping || status.Transitions != 3 {
+		t.Fatalf("expected flapping with 3 transitions, got %+v", status)
+	}
+
+	// Stable state inside the window keeps flapping.
+	changes := app.recordDependencyMonitor(dependencyMonitorTestReport(DEPENDENCY_STATE_UNHEALTHY), now.Add(30*time.Second), app.logger)
+	if len(changes) != 0 {
+		t.Errorf("expected no change, got %+v", changes)
+	}
+	if status := app.DependencyStatus("db"); !status.Flapping {
+		t.Error("expected flapping inside the window")
+	}
+
+	// Stable state after the window stops flapping.
+	app.recordDependencyMonitor(dependencyMonitorTestReport(DEPENDENCY_STATE_UNHEALTHY), now.Add(2*time.Minute), app.logger)
+	if status := app.DependencyStatus("db"); status.Flapping || status.Transitions != 0 {
+		t.Errorf("expected not flapping without transitions, got %+v", status)
+	}
+}
+
+func TestDependencyMonitorNotifyOk(t *testing.T) {
+	db := &dependencyHealthTest{dependencyTest: dependencyTest{name: "db"}, healthy: true}
+	app := &App{Config: &Config{HTTPHealthTimeout: 1000, DependencyMonitorFlapThreshold: 4, DependencyMonitorFlapWindow: 300}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	app.dependencyRegistry = []Dependency{db}
+	app.dependencyMonitor.entries = make(map[string]*dependencyMonitorEntry)
+
+	type change struct{ old, new DependencyState }
+	var changes []change
+	app.OnDependencyStateChange(func(name string, old, new DependencyState) {
+		changes = append(changes, change{old, new})
+	})
+	app.OnDependencyStateChange(func(name string, old, new DependencyState) { panic("handler panic") })
+
+	app.checkDependencyMonitor(context.Background(), app.logger)
+	app.checkDependencyMonitor(context.Background(), app.logger)
+	db.healthy = false
+	app.checkDependencyMonitor(context.Background(), app.logger)
+
+	expected := []change{
+		{DEPENDENCY_STATE_UNKNOWN, DEPENDENCY_STATE_HEALTHY},
+		{DEPENDENCY_STATE_HEALTHY, DEPENDENCY_STATE_UNHEALTHY},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change %d expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+	if status := app.DependencyStatus("db"); status.State != DEPENDENCY_STATE_UNHEALTHY || status.Transitions != 1 {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+// dependencyRecoveryTest is a dependency that fails to open for the first `fails` attempts and is healthy once it is opened.
+type dependencyRecoveryTest struct {
+	dependencyFailTest
+}
+
+func (d *dependencyRecoveryTest) HealthCheck(ctx context.Context) *DependencyStats {
+	if d.opens.Load() <= d.fails {
+		return nil
+	}
+	return &DependencyStats{PINGResponse: "PONG"}
+}
+
+func TestDependencyMonitorRecoveryOk(t *testing.T) {
+	cache := &dependencyRecoveryTest{dependencyFailTest{dependencyTest: dependencyTest{name: "cache"}, fails: 2}}
+	queue := &dependencyRecoveryTest{dependencyFailTest{dependencyTest: dependencyTest{name: "queue", dependsOn: []string{"cache"}}}}
+	app := &App{Config: &Config{HTTPHealthTimeout: 1000, DependencyMonitorFlapThreshold: 4, DependencyMonitorFlapWindow: 300}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	app.dependencyRegistry = []Dependency{cache, queue}
+	app.dependencyMonitor.entries = make(map[string]*dependencyMonitorEntry)
+	if err := app.openDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	type change struct {
+		name     string
+		old, new DependencyState
+	}
+	var changes []change
+	app.OnDependencyStateChange(func(name string, old, new DependencyState) {
+		changes = append(changes, change{name, old, new})
+	})
+
+	// The degraded dependency is reopened by every check, the dependent waits its required dependency.
+	app.checkDependencyMonitor(context.Background(), app.logger)
+	if n := cache.opens.Load(); n != 2 {
+		t.Errorf("expected degraded dependency is reopened, got %d attempts", n)
+	}
+	if n := queue.opens.Load(); n != 0 {
+		t.Errorf("expected dependent is not opened, got %d attempts", n)
+	}
+	app.checkDependencyMonitor(context.Background(), app.logger)
+
+	expected := []change{
+		{"cache", DEPENDENCY_STATE_UNKNOWN, DEPENDENCY_STATE_DEGRADED},
+		{"queue", DEPENDENCY_STATE_UNKNOWN, DEPENDENCY_STATE_DEGRADED},
+		{"cache", DEPENDENCY_STATE_DEGRADED, DEPENDENCY_STATE_HEALTHY},
+		{"queue", DEPENDENCY_STATE_DEGRADED, DEPENDENCY_STATE_HEALTHY},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change %d expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+	if len(app.dependencyDegraded) != 0 || len(app.dependencyOpened) != 2 ||
+		app.dependencyOpened[0] != cache || app.dependencyOpened[1] != queue {
+		t.Errorf("expected recovered dependencies are opened, got %v %v", app.dependencyDegraded, app.dependencyOpened)
+	}
+}
+
+// dependencyHangTest is a dependency that fails the first open and blocks the next opens until it is released.
+type dependencyHangTest struct {
+	dependencyBlockTest
+}
+
+func (d *dependencyHangTest) Open() error {
+	if d.opens.Load() == 0 {
+		d.opens.Add(1)
+		return errors.New("connection refused")
+	}
+	return d.dependencyBlockTest.Open()
+}
+
+func TestDependencyMonitorReopenPendingOk(t *testing.T) {
+	config := &Config{HTTPHealthTimeout: 1000, DependencyOpenTimeout: 1, DependencyCloseTimeout: 1, DependencyMonitorInterval: 1}
+	db := &dependencyHangTest{dependencyBlockTest{dependencyTest: dependencyTest{name: "db"}, release: make(chan struct{})}}
+	app := newLifecycleTestApp(config)
+	app.dependencyRegistry = []Dependency{db}
+	app.dependencyMonitor.entries = make(map[string]*dependencyMonitorEntry)
+	if err := app.openDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The check returns when its context is done, the next check skips the running open call.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	app.checkDependencyMonitor(ctx, app.logger)
+	app.checkDependencyMonitor(context.Background(), app.logger)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected checks are not blocked by the running open call, took %s", elapsed)
+	}
+	if n := db.opens.Load(); n != 2 {
+		t.Errorf("expected 2 open calls, got %d", n)
+	}
+	if n := db.concurrent.Load(); n != 1 {
+		t.Errorf("expected no concurrent open call, got %d", n)
+	}
+	if _, degraded := app.dependencyDegradedError("db"); !degraded {
+		t.Error("expected db is still degraded")
+	}
+
+	// The running open call is taken as opened once it succeeds.
+	close(db.release)
+	<-app.dependencyMonitor.pending["db"].done
+	app.checkDependencyMonitor(context.Background(), app.logger)
+	if n := db.opens.Load(); n != 2 {
+		t.Errorf("expected the succeeded call is not opened again, got %d open calls", n)
+	}
+	if len(app.dependencyOpened) != 1 {
+		t.Errorf("expected db is opened, got %v", app.dependencyOpened)
+	}
+}
+
+func TestDependencyMonitorStopOk(t *testing.T) {
+	config := &Config{HTTPHealthTimeout: 1000, DependencyOpenTimeout: 5, DependencyCloseTimeout: 1, DependencyMonitorInterval: 1}
+	db := &dependencyHangTest{dependencyBlockTest{dependencyTest: dependencyTest{name: "db"}, release: make(chan struct{})}}
+	app := newLifecycleTestApp(config)
+	app.dependencyRegistry = []Dependency{db}
+	if err := app.openDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stopping does not wait the open timeout of the hanging open call.
+	app.startDependencyMonitor()
+	time.Sleep(200 * time.Millisecond)
+	start := time.Now()
+	app.stopDependencyMonitor()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected monitor is stopped promptly, took %s", elapsed)
+	}
+	if n := db.opens.Load(); n != 2 {
+		t.Errorf("expected 2 open calls, got %d", n)
+	}
+
+	// The abandoned call that succeeds late is closed.
+	close(db.release)
+	app.closeDependencies()
+	if n := db.closes.Load(); n != 1 {
+		t.Errorf("expected the late opened dependency is closed, got %d", n)
+	}
+}
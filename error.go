@@ -0,0 +1,151 @@
+package qore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// Error defines typed domain error with stable business code & HTTP(s) status,
+// the message is public and returned to the client while the cause is internal.
+//
+//	var ErrUserNotFound = qore.NewError(http.StatusNotFound, "USER_NOT_FOUND", "user is not found")
+//
+//	return nil, ErrUserNotFound.Wrap(err)
+type Error struct {
+	// Code is stable business error code, e.g. `USER_NOT_FOUND`.
+	Code string
+	// Status is HTTP(s) status of the error, 4xx or 5xx.
+	Status int
+	// Message is public message of the error.
+	Message string
+	// Cause is internal cause of the error, it is not returned to the client in production.
+	Cause error
+
+	// origin is the sentinel error of the copy.
+	origin *Error
+}
+
+// NewError creates typed domain error.
+func NewError(status int, code, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, e.Cause.Error())
+}
+
+// Unwrap returns the internal cause.
+func (e *Error) Unwrap() error { return e.Cause }
+
+// Is returns true if target is the same `Error` or its copy, so the wrapped copy still matches
+// its sentinel error. The other `Error` matches only by the same non-empty code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t == nil {
+		return false
+	}
+	return t.sentinel() == e.sentinel() || (e.Code != "" && t.Code == e.Code)
+}
+
+// Wrap returns copy of the error with the internal cause.
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := e.copy()
+	wrapped.Cause = cause
+	return wrapped
+}
+
+// WithMessage returns copy of the error with the public message.
+func (e *Error) WithMessage(message string) *Error {
+	wrapped := e.copy()
+	wrapped.Message = message
+	return wrapped
+}
+
+// sentinel returns the sentinel error of the copy or the error itself.
+func (e *Error) sentinel() *Error {
+	if e.origin != nil {
+		return e.origin
+	}
+	return e
+}
+
+// copy returns copy of the error that keeps its sentinel error.
+func (e *Error) copy() *Error {
+	copied := *e
+	copied.origin = e.sentinel()
+	return &copied
+}
+
+type errorRegistryEntry struct {
+	target error
+	err    *Error
+}
+
+// errorRegistry maps sentinel error into typed domain error.
+type errorRegistry struct {
+	mu      sync.RWMutex
+	entries []errorRegistryEntry
+}
+
+// register maps the sentinel error, the later registration of the same error replaces the former.
+// The error of non-comparable type is never the same error, it is matched by its `Is` method like `errors.Is`.
+func (r *errorRegistry) register(target error, err *Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comparable := reflect.TypeOf(target).Comparable()
+	for i, entry := range r.entries {
+		if comparable && entry.target == target {
+			r.entries[i].err = err
+			return
+		}
+	}
+	r.entries = append(r.entries, errorRegistryEntry{target: target, err: err})
+}
+
+// resolve returns typed domain error of err by walking the `errors.As` & `errors.Is` chain,
+// typed `Error` in the chain takes precedence over the registered sentinel error.
+func (r *errorRegistry) resolve(err error) (*Error, bool) {
+	var qerr *Error
+	if errors.As(err, &qerr) {
+		return qerr, true
+	}
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range r.entries {
+		if errors.Is(err, entry.target) {
+			resolved := entry.err.copy()
+			resolved.Cause = err
+			return resolved, true
+		}
+	}
+	return nil, false
+}
+
+// RegisterError maps sentinel error into HTTP(s) status & business code, so `ApiResponse.Error`
+// returns the mapped status & code for error that wraps the sentinel error. Empty message uses
+// the HTTP(s) status text as the public message.
+//
+//	app.RegisterError(sql.ErrNoRows, http.StatusNotFound, "NOT_FOUND", "resource is not found")
+func (app *App) RegisterError(target error, status int, code string, message ...string) error {
+	if target == nil {
+		return errors.New("error target is required")
+	} else if status < 400 || status > 599 {
+		return fmt.Errorf("error status %d must be 4xx or 5xx", status)
+	}
+	msg := http.StatusText(status)
+	if len(message) > 0 && !ValidationIsEmpty(message[0]) {
+		msg = message[0]
+	}
+	app.errorRegistry.register(target, NewError(status, code, msg))
+	return nil
+}
@@ -0,0 +1,141 @@
+package qore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var errTestUserNotFound = NewError(http.StatusNotFound, "USER_NOT_FOUND", "user is not found")
+
+func TestErrorRegistryOk(t *testing.T) {
+	for _, production := range []bool{false, true} {
+		app := &App{Config: &Config{AppProduction: production}, httpServers: make(map[string]*httpServer)}
+		app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+		if err := app.AddHttpServer(HTTP_SERVER_DEFAULT, HttpServerConfig{Port: 3100}); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.RegisterError(sql.ErrNoRows, http.StatusNotFound, "NOT_FOUND"); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.RegisterError(sql.ErrNoRows, http.StatusOK, "OK"); err == nil {
+			t.Error("expected invalid status error")
+		}
+
+		tests := []struct {
+			err        error
+			status     int
+			code       string
+			message    string
+			messageDev string
+		}{
+			{
+				err:    errTestUserNotFound.Wrap(errors.New("select failed")),
+				status: http.StatusNotFound, code: "USER_NOT_FOUND",
+				message: "user is not found", messageDev: "user is not found: select failed",
+			},
+			{
+				err:    fmt.Errorf("find user: %w", errTestUserNotFound),
+				status: http.StatusNotFound, code: "USER_NOT_FOUND",
+				message: "user is not found", messageDev: "user is not found",
+			},
+			{
+				err:    fmt.Errorf("find order: %w", sql.ErrNoRows),
+				status: http.StatusNotFound, code: "NOT_FOUND",
+				message: "Not Found", messageDev: "Not Found: find order: sql: no rows in result set",
+			},
+			{
+				err:    errors.New("connection refused"),
+				status: http.StatusInternalServerError, code: "500",
+				message: "Internal Server Error", messageDev: "connection refused",
+			},
+		}
+		for _, test := range tests {
+			rec := httptest.NewRecorder()
+			c := &httpContextImpl{
+				Context: app.httpServer.core.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec),
+				server:  app.httpServer,
+				logger:  app.logger,
+			}
+			if err := c.Api().Error(test.err).Response(); err != nil {
+				t.Fatal(err)
+			}
+
+			var response ApiResponseDefault
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			message := test.messageDev
+			if production {
+				message = test.message
+			}
+			if rec.Code != test.status || response.Code != test.code || response.Error != message {
+				t.Errorf("production %v %q: unexpected %d %s %v", production, test.err, rec.Code, response.Code, response.Error)
+			}
+		}
+	}
+}
+
+func TestErrorIsOk(t *testing.T) {
+	errNotFound := NewError(http.StatusNotFound, "", "not found")
+	errGone := NewError(http.StatusNotFound, "", "gone")
+	errUserNotFound := NewError(http.StatusConflict, "USER_NOT_FOUND", "user is not found")
+
+	tests := []struct {
+		err    error
+		target error
+		is     bool
+	}{
+		{errNotFound, errNotFound, true},
+		{errNotFound.Wrap(errors.New("select failed")).WithMessage("missing"), errNotFound, true},
+		{fmt.Errorf("find: %w", errNotFound.Wrap(nil)), errNotFound, true},
+		{errNotFound, errGone, false},
+		{errGone.Wrap(errors.New("select failed")), errNotFound, false},
+		{errTestUserNotFound, errUserNotFound, true},
+		{errTestUserNotFound, errNotFound, false},
+	}
+	for _, test := range tests {
+		if is := errors.Is(test.err, test.target); is != test.is {
+			t.Errorf("%q is %q: expected %v, got %v", test.err, test.target, test.is, is)
+		}
+	}
+
+	// The registered sentinel error of the same status is resolved to its own entry.
+	var registry errorRegistry
+	errA, errB := errors.New("a"), errors.New("b")
+	registry.register(errA, errNotFound)
+	registry.register(errB, errGone)
+	if resolved, ok := registry.resolve(errB); !ok || resolved.Message != "gone" || !errors.Is(resolved, errGone) {
+		t.Errorf("unexpected resolved error %v", resolved)
+	}
+}
+
+// errorTestValidation is the error of non-comparable type that is matched by its `Is` method.
+type errorTestValidation struct {
+	fields []string
+}
+
+func (e errorTestValidation) Error() string { return fmt.Sprintf("invalid fields %v", e.fields) }
+
+func (e errorTestValidation) Is(target error) bool {
+	_, ok := target.(errorTestValidation)
+	return ok
+}
+
+func TestErrorRegistryNonComparableOk(t *testing.T) {
+	var registry errorRegistry
+	registry.register(errorTestValidation{fields: []string{"name"}}, NewError(http.StatusBadRequest, "INVALID", "invalid"))
+	registry.register(errorTestValidation{fields: []string{"email"}}, NewError(http.StatusUnprocessableEntity, "INVALID", "invalid"))
+	if len(registry.entries) != 2 {
+		t.Errorf("expected non-comparable errors are registered, got %d entries", len(registry.entries))
+	}
+
+	resolved, ok := registry.resolve(fmt.Errorf("create user: %w", errorTestValidation{fields: []string{"age"}}))
+	if !ok || resolved.Status != http.StatusBadRequest {
+		t.Errorf("expected non-comparable error is resolved by its Is method, got %+v %v", resolved, ok)
+	}
+}
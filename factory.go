@@ -13,22 +13,52 @@ func New() *App {
 
 	// Config load.
 	app.Config = loadConfig()
-	if app.Config.HTTPPort > 0 {
-		app.addresses = append(app.addresses, fmt.Sprintf(":%d", app.Config.HTTPPort))
-	}
 
 	// Utility
 	app.logger = setupLogger(app.Config)
+	if err := app.setupTracing(); err != nil {
+		app.Logger().Error(err.Error())
+	}
+	app.setupMetrics()
 
 	// Application server.
 	if app.Config.HTTPPort > 0 {
 		// HTTP server.
-		app.httpServer = newHttpServer()
-		app.httpServer.autoTLS = app.Config.HTTPAutoTLS
-		app.httpServer.certPath = app.Config.HTTPCertPath
-		app.httpServer.keyPath = app.Config.HTTPKeyPath
-		app.httpServer.shutdownTimeout = time.Duration(app.Config.ShutdownTimeout) * time.Second
-		app.httpServer.core.Debug = !app.Config.AppProduction
+		if err := app.AddHttpServer(HTTP_SERVER_DEFAULT, HttpServerConfig{
+			Port:     app.Config.HTTPPort,
+			AutoTLS:  app.Config.HTTPAutoTLS,
+			CertPath: app.Config.HTTPCertPath,
+			KeyPath:  app.Config.HTTPKeyPath,
+		}); err != nil {
+			app.Logger().Error(err.Error())
+		}
+	}
+	if app.Config.HTTPAdminPort > 0 {
+		// HTTP admin server, the TLS is configured by its own config.
+		if err := app.AddHttpServer(HTTP_SERVER_ADMIN, HttpServerConfig{
+			Port:     app.Config.HTTPAdminPort,
+			AutoTLS:  app.Config.HTTPAdminAutoTLS,
+			CertPath: app.Config.HTTPAdminCertPath,
+			KeyPath:  app.Config.HTTPAdminKeyPath,
+		}); err != nil {
+			app.Logger().Error(err.Error())
+		}
+	}
+
+	// Health probe.
+	app.setHttpHealthRoutes()
+
+	// Metrics endpoint.
+	app.setHttpMetricsRoute()
+
+	// OpenAPI document.
+	app.setHttpOpenAPIRoute()
+
+	if app.Config.GRPCPort > 0 {
+		// gRPC server.
+		app.grpcServer = newGrpcServer()
+		app.grpcServer.shutdownTimeout = time.Duration(app.Config.ShutdownTimeout) * time.Second
+		app.addServer("grpc", fmt.Sprintf(":%d", app.Config.GRPCPort), app.grpcServer)
 	}
 
 	return app
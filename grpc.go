@@ -0,0 +1,96 @@
+package qore
+
+import (
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type grpcServer struct {
+	core            *grpc.Server
+	options         []grpc.ServerOption
+	shutdownTimeout time.Duration
+}
+
+func newGrpcServer() *grpcServer {
+	return &grpcServer{
+		options: []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(grpcUnaryTraceInterceptor),
+			grpc.ChainStreamInterceptor(grpcStreamTraceInterceptor),
+		},
+	}
+}
+
+// server returns the gRPC server core, the core is created on first call
+// so the server options must be set before registering the services.
+func (s *grpcServer) server() *grpc.Server {
+	if s.core == nil {
+		s.core = grpc.NewServer(s.options...)
+	}
+	return s.core
+}
+
+func (s *grpcServer) start(lfn func() (listener net.Listener, err error), logger *logger) {
+	logger = logger.With(slog.String("scope", "grpc server"))
+	listener, err := lfn()
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	core := s.server()
+	go func() {
+		if err := core.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+			logger.Error(err.Error())
+		}
+	}()
+	logger.Debug("gRPC server running...")
+}
+
+func (s *grpcServer) stop(logger *logger) {
+	if s.core == nil {
+		return
+	}
+	logger = logger.With(slog.String("scope", "grpc server"))
+
+	// Graceful stop with timeout, then force stop.
+	done := make(chan struct{})
+	go func() {
+		s.core.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.shutdownTimeout):
+		logger.Warn("gRPC server graceful stop timed out, force stopping")
+		s.core.Stop()
+	}
+	logger.Debug("gRPC server was stoped")
+}
+
+// SetGrpcServerOptions will set given option(s) to the gRPC server, like additional interceptors.
+// It must be called before registering any gRPC services.
+func (app *App) SetGrpcServerOptions(options ...grpc.ServerOption) {
+	if app.grpcServer == nil {
+		return
+	}
+	if app.grpcServer.core != nil {
+		app.Logger().Warn("gRPC server options must be set before registering the services")
+		return
+	}
+	app.grpcServer.options = append(app.grpcServer.options, options...)
+}
+
+// SetGrpcServices gives gRPC service registrar that can be used for registering gRPC services.
+//
+//	app.SetGrpcServices(func(registrar grpc.ServiceRegistrar) {
+//		pb.RegisterUserServiceServer(registrar, m.grpcHandler)
+//	})
+func (app *App) SetGrpcServices(fn func(registrar grpc.ServiceRegistrar)) {
+	if app.grpcServer == nil {
+		return
+	}
+	fn(app.grpcServer.server())
+}
@@ -0,0 +1,81 @@
+package qore
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcTraceIDFromMetadata returns trace ID from the incoming gRPC metadata,
+// the invalid trace ID is ignored since it is echoed into the response header & log.
+func grpcTraceIDFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(GRPC_METADATA_TRACE_ID); len(values) > 0 && ValidationIsTraceID(values[0]) {
+		return values[0]
+	}
+	return ""
+}
+
+// grpcTraceContext returns context with trace ID from the incoming gRPC metadata,
+// a new trace ID is generated when the metadata does not have it.
+func grpcTraceContext(ctx context.Context) context.Context {
+	traceID := grpcTraceIDFromMetadata(ctx)
+	if traceID == "" {
+		traceID, _ = StringAlphaNumRandom(32)
+	}
+	return context.WithValue(ctx, CTX_TRACE_ID, traceID)
+}
+
+// grpcStreamWrapper wraps `grpc.ServerStream` to override the stream context.
+type grpcStreamWrapper struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcStreamWrapper) Context() context.Context { return s.ctx }
+
+// grpcUnaryTraceInterceptor propagates trace ID from the incoming metadata into the context
+// and send it back through the response header.
+func grpcUnaryTraceInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	ctx = grpcTraceContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(GRPC_METADATA_TRACE_ID, ContextGetTraceID(ctx)))
+	return handler(ctx, req)
+}
+
+// grpcStreamTraceInterceptor propagates trace ID from the incoming metadata into the stream context
+// and send it back through the response header.
+func grpcStreamTraceInterceptor(
+	srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	ctx := grpcTraceContext(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(GRPC_METADATA_TRACE_ID, ContextGetTraceID(ctx)))
+	return handler(srv, &grpcStreamWrapper{ServerStream: ss, ctx: ctx})
+}
+
+// GrpcClientUnaryTraceInterceptor propagates trace ID from the context into the outgoing gRPC metadata.
+func GrpcClientUnaryTraceInterceptor(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	if traceID, ok := ctx.Value(CTX_TRACE_ID).(string); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, GRPC_METADATA_TRACE_ID, traceID)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// GrpcClientStreamTraceInterceptor propagates trace ID from the context into the outgoing gRPC metadata.
+func GrpcClientStreamTraceInterceptor(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+	streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if traceID, ok := ctx.Value(CTX_TRACE_ID).(string); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, GRPC_METADATA_TRACE_ID, traceID)
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
@@ -0,0 +1,158 @@
+package qore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcTransportStreamTest captures the header that is set by the unary interceptor.
+type grpcTransportStreamTest struct {
+	header metadata.MD
+}
+
+func (s *grpcTransportStreamTest) Method() string { return "/test.Service/Method" }
+func (s *grpcTransportStreamTest) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+func (s *grpcTransportStreamTest) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+func (s *grpcTransportStreamTest) SetTrailer(md metadata.MD) error { return nil }
+
+// grpcServerStreamTest captures the header that is set by the stream interceptor.
+type grpcServerStreamTest struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *grpcServerStreamTest) Context() context.Context { return s.ctx }
+func (s *grpcServerStreamTest) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func grpcIncomingTest(traceID string) context.Context {
+	if traceID == "" {
+		return metadata.NewIncomingContext(context.Background(), metadata.MD{})
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(GRPC_METADATA_TRACE_ID, traceID))
+}
+
+var grpcTraceIDCases = []struct {
+	name     string
+	incoming string
+	keep     bool
+}{
+	{"valid", "abc-123_DEF.456", true},
+	{"missing", "", false},
+	{"too long", strings.Repeat("a", TRACE_ID_MAX_LENGTH+1), false},
+	{"invalid character", "abc\r\nlevel=ERROR", false},
+	{"space", "abc 123", false},
+}
+
+func TestGrpcUnaryTraceInterceptorOk(t *testing.T) {
+	for _, c := range grpcTraceIDCases {
+		t.Run(c.name, func(t *testing.T) {
+			stream := &grpcTransportStreamTest{}
+			ctx := grpc.NewContextWithServerTransportStream(grpcIncomingTest(c.incoming), stream)
+
+			var got string
+			_, err := grpcUnaryTraceInterceptor(ctx, nil, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req any) (any, error) {
+					got = ContextGetTraceID(ctx)
+					return nil, nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.keep && got != c.incoming {
+				t.Errorf("expected trace ID %q, got %q", c.incoming, got)
+			}
+			if !c.keep && (got == c.incoming || len(got) != 32) {
+				t.Errorf("expected generated trace ID, got %q", got)
+			}
+			if header := stream.header.Get(GRPC_METADATA_TRACE_ID); len(header) != 1 || header[0] != got {
+				t.Errorf("expected response header %q, got %v", got, header)
+			}
+		})
+	}
+}
+
+func TestGrpcStreamTraceInterceptorOk(t *testing.T) {
+	for _, c := range grpcTraceIDCases {
+		t.Run(c.name, func(t *testing.T) {
+			stream := &grpcServerStreamTest{ctx: grpcIncomingTest(c.incoming)}
+
+			var got string
+			err := grpcStreamTraceInterceptor(nil, stream, &grpc.StreamServerInfo{},
+				func(srv any, ss grpc.ServerStream) error {
+					got = ContextGetTraceID(ss.Context())
+					return nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.keep && got != c.incoming {
+				t.Errorf("expected trace ID %q, got %q", c.incoming, got)
+			}
+			if !c.keep && (got == c.incoming || len(got) != 32) {
+				t.Errorf("expected generated trace ID, got %q", got)
+			}
+			if header := stream.header.Get(GRPC_METADATA_TRACE_ID); len(header) != 1 || header[0] != got {
+				t.Errorf("expected response header %q, got %v", got, header)
+			}
+		})
+	}
+}
+
+func TestGrpcClientTraceInterceptorOk(t *testing.T) {
+	outgoing := func(ctx context.Context) []string {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		return md.Get(GRPC_METADATA_TRACE_ID)
+	}
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{"with trace ID", context.WithValue(context.Background(), CTX_TRACE_ID, "trace-1"), []string{"trace-1"}},
+		{"without trace ID", context.Background(), nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var unary []string
+			err := GrpcClientUnaryTraceInterceptor(c.ctx, "/test.Service/Method", nil, nil, nil,
+				func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					unary = outgoing(ctx)
+					return nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(unary, ",") != strings.Join(c.want, ",") {
+				t.Errorf("unary: expected metadata %v, got %v", c.want, unary)
+			}
+
+			var stream []string
+			_, err = GrpcClientStreamTraceInterceptor(c.ctx, &grpc.StreamDesc{}, nil, "/test.Service/Stream",
+				func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+					stream = outgoing(ctx)
+					return nil, nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(stream, ",") != strings.Join(c.want, ",") {
+				t.Errorf("stream: expected metadata %v, got %v", c.want, stream)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrHealthCheckNoStats = errors.New("health check returns no stats")
+
+// HealthReport defines aggregated health report of the application.
+type HealthReport struct {
+	Status       DependencyState          `json:"status" xml:"status"`
+	Dependencies []DependencyHealthReport `json:"dependencies,omitempty" xml:"dependencies>dependency,omitempty"`
+	Workers      *WorkerHealthReport      `json:"workers,omitempty" xml:"workers,omitempty"`
+}
+
+// WorkerHealthReport defines health report of the background workers.
+type WorkerHealthReport struct {
+	Total    int            `json:"total" xml:"total"`
+	Running  int            `json:"running" xml:"running"`
+	Failed   int            `json:"failed" xml:"failed"`
+	Restarts int            `json:"restarts" xml:"restarts"`
+	Workers  []WorkerStatus `json:"workers" xml:"worker"`
+}
+
+// DependencyHealthReport defines health report of a dependency.
+type DependencyHealthReport struct {
+	Name     string           `json:"name" xml:"name"`
+	State    DependencyState  `json:"state" xml:"state"`
+	Critical bool             `json:"critical" xml:"critical"`
+	Error    string           `json:"error,omitempty" xml:"error,omitempty"`
+	Stats    *DependencyStats `json:"stats,omitempty" xml:"stats,omitempty"`
+}
+
+// healthCheckDependency calls `Dependency.HealthCheck` with given timeout,
+// it does not wait the dependency that ignores the context deadline.
+func healthCheckDependency(ctx context.Context, dependency Dependency, timeout time.Duration) (*DependencyStats, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result := make(chan *DependencyStats, 1)
+	go func() {
+		var stats *DependencyStats
+		TraceDependencyCall(ctx, dependency.Name(), "health_check", func(ctx context.Context) error {
+			if stats = dependency.HealthCheck(ctx); stats == nil {
+				return ErrHealthCheckNoStats
+			}
+			return nil
+		})
+		result <- stats
+	}()
+	select {
+	case stats := <-result:
+		if stats == nil {
+			return nil, ErrHealthCheckNoStats
+		}
+		return stats, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("health check of dependency %s: %w", dependency.Name(), ctx.Err())
+	}
+}
+
+// dependencyCritical returns true when the dependency is critical, see `DependencyCritical`.
+func dependencyCritical(dependency Dependency) bool {
+	if d, ok := dependency.(DependencyCritical); ok {
+		return d.Critical()
+	}
+	return true
+}
+
+// HealthCheck fans out `Dependency.HealthCheck` to every registered dependency and aggregates the result.
+// The report status is unhealthy when any critical dependency is unhealthy or degraded, and degraded
+// when only the non-critical dependency is unhealthy or degraded, see `DependencyCritical`.
+func (app *App) HealthCheck(ctx context.Context) *HealthReport {
+	timeout := time.Duration(app.Config.HTTPHealthTimeout) * time.Millisecond
+	report := &HealthReport{
+		Status:       DEPENDENCY_STATE_HEALTHY,
+		Dependencies: make([]DependencyHealthReport, len(app.dependencyRegistry)),
+	}
+
+	var wg sync.WaitGroup
+	for i, dependency := range app.dependencyRegistry {
+		report.Dependencies[i] = DependencyHealthReport{
+			Name:     dependency.Name(),
+			State:    DEPENDENCY_STATE_HEALTHY,
+			Critical: dependencyCritical(dependency),
+		}
+		if err, degraded := app.dependencyDegradedError(dependency.Name()); degraded {
+			report.Dependencies[i].State = DEPENDENCY_STATE_DEGRADED
+			report.Dependencies[i].Error = err.Error()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats, err := healthCheckDependency(ctx, dependency, timeout)
+			report.Dependencies[i].Stats = stats
+			if err != nil {
+				report.Dependencies[i].State = DEPENDENCY_STATE_UNHEALTHY
+				report.Dependencies[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Background worker.
+	if statuses := app.WorkerStatuses(); len(statuses) > 0 {
+		report.Workers = &WorkerHealthReport{Total: len(statuses), Workers: statuses}
+		for _, status := range statuses {
+			switch status.State {
+			case WORKER_STATE_RUNNING:
+				report.Workers.Running++
+			case WORKER_STATE_FAILED:
+				report.Workers.Failed++
+			}
+			report.Workers.Restarts += status.Restarts
+		}
+	}
+
+	for _, dependency := range report.Dependencies {
+		if dependency.State == DEPENDENCY_STATE_HEALTHY {
+			continue
+		}
+		if dependency.Critical {
+			report.Status = DEPENDENCY_STATE_UNHEALTHY
+			break
+		}
+		report.Status = DEPENDENCY_STATE_DEGRADED
+	}
+	return report
+}
+
+// unhealthyError returns error that describes unhealthy critical dependency on the report.
+func (r *HealthReport) unhealthyError() error {
+	var names []string
+	for _, dependency := range r.Dependencies {
+		if dependency.Critical && dependency.State != DEPENDENCY_STATE_HEALTHY {
+			names = append(names, dependency.Name)
+		}
+	}
+	if len(names) == 0 {
+		return errors.New("application is not ready")
+	}
+	return fmt.Errorf("critical dependency unhealthy: %s", strings.Join(names, ", "))
+}
+
+// setHttpHealthRoutes mounts health, readiness and liveness probe into the HTTP(s) server.
+func (app *App) setHttpHealthRoutes() {
+	if !app.Config.HTTPHealthEnabled {
+		return
+	}
+	app.SetHttpRoutesOn(app.Config.HTTPHealthServer, func(router *HttpRouter) {
+		// Health, full report of the dependencies.
+		if !ValidationIsEmpty(app.Config.HTTPHealthPath) {
+			router.Get(app.Config.HTTPHealthPath, HttpHanlderChain(func(c HttpContext) error {
+				report := app.HealthCheck(c.Request().Context())
+				if report.Status == DEPENDENCY_STATE_UNHEALTHY {
+					return c.Api().ServerError(HttpStatusServiceUnavailable, report.unhealthyError()).
+						WithAdditional(report).
+						Response()
+				}
+				return c.Api().Success(HttpStatusOK, report).Response()
+			}))
+		}
+
+		// Readiness, the application is started & the critical dependencies are healthy.
+		if !ValidationIsEmpty(app.Config.HTTPReadyPath) {
+			router.Get(app.Config.HTTPReadyPath, HttpHanlderChain(func(c HttpContext) error {
+				report := app.HealthCheck(c.Request().Context())
+				if !app.ready.Load() || report.Status == DEPENDENCY_STATE_UNHEALTHY {
+					return c.Api().ServerError(HttpStatusServiceUnavailable, report.unhealthyError()).
+						WithAdditional(report).
+						Response()
+				}
+				return c.Api().Success(HttpStatusOK, report).Response()
+			}))
+		}
+
+		// Liveness, the process is alive and able to serve the request.
+		if !ValidationIsEmpty(app.Config.HTTPLivePath) {
+			router.Get(app.Config.HTTPLivePath, HttpHanlderChain(func(c HttpContext) error {
+				return c.Api().Success(HttpStatusOK, &HealthReport{Status: DEPENDENCY_STATE_HEALTHY}).Response()
+			}))
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package qore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// dependencyHealthTest is a dependency with the configurable health.
+type dependencyHealthTest struct {
+	dependencyTest
+	healthy     bool
+	policy      DependencyPolicy
+	nonCritical bool
+}
+
+func (d *dependencyHealthTest) HealthCheck(ctx context.Context) *DependencyStats {
+	if !d.healthy {
+		return nil
+	}
+	return &DependencyStats{PINGResponse: "PONG"}
+}
+
+func (d *dependencyHealthTest) OpenPolicy() DependencyPolicy { return d.policy }
+
+func (d *dependencyHealthTest) Critical() bool { return !d.nonCritical }
+
+func newHealthTestApp(t *testing.T, dependencies ...Dependency) *App {
+	t.Helper()
+	app := &App{Config: &Config{
+		HTTPHealthEnabled: true,
+		HTTPHealthPath:    "/healthz",
+		HTTPReadyPath:     "/readyz",
+		HTTPLivePath:      "/livez",
+		HTTPHealthTimeout: 1000,
+	}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	app.dependencyRegistry = dependencies
+	if err := app.AddHttpServer(HTTP_SERVER_DEFAULT, HttpServerConfig{Port: 3100}); err != nil {
+		t.Fatal(err)
+	}
+	app.setHttpHealthRoutes()
+	return app
+}
+
+func healthTestRequest(t *testing.T, app *App, path string) (int, *ApiResponseDefault) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	app.httpServer.core.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	body := new(ApiResponseDefault)
+	if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+		t.Fatalf("%s: %v: %s", path, err, rec.Body.String())
+	}
+	return rec.Code, body
+}
+
+func TestHealthEndpointOk(t *testing.T) {
+	app := newHealthTestApp(t,
+		&dependencyHealthTest{dependencyTest: dependencyTest{name: "db"}, healthy: true, policy: DEPENDENCY_POLICY_ABORT},
+		&dependencyHealthTest{dependencyTest: dependencyTest{name: "cache"}, nonCritical: true},
+	)
+
+	// The non-critical unhealthy dependency is degraded.
+	status, body := healthTestRequest(t, app, "/healthz")
+	if status != http.StatusOK || !body.Success {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+	report, _ := body.Data.(map[string]any)
+	if report["status"] != string(DEPENDENCY_STATE_DEGRADED) {
+		t.Errorf("expected degraded report, got %v", report["status"])
+	}
+}
+
+func TestHealthEndpointUnhealthyOk(t *testing.T) {
+	// The dependency is critical by default, regardless of its open policy.
+	app := newHealthTestApp(t, &dependencyTest{name: "db"})
+
+	// The 503 body keeps the dependency report.
+	status, body := healthTestRequest(t, app, "/healthz")
+	if status != http.StatusServiceUnavailable || body.Success {
+		t.Fatalf("expected status 503, got %d", status)
+	}
+	if body.Meta == nil {
+		t.Fatal("expected the report in the response meta")
+	}
+	report, _ := body.Meta.Additional.(map[string]any)
+	if report["status"] != string(DEPENDENCY_STATE_UNHEALTHY) {
+		t.Errorf("expected unhealthy report, got %v", report["status"])
+	}
+	dependencies, _ := report["dependencies"].([]any)
+	if len(dependencies) != 1 {
+		t.Fatalf("expected 1 dependency report, got %d", len(dependencies))
+	}
+	if dependency, _ := dependencies[0].(map[string]any); dependency["name"] != "db" || dependency["critical"] != true {
+		t.Errorf("expected critical db report, got %v", dependency)
+	}
+
+	// Liveness does not check the dependencies.
+	if status, _ := healthTestRequest(t, app, "/livez"); status != http.StatusOK {
+		t.Errorf("expected liveness status 200, got %d", status)
+	}
+}
+
+func TestReadinessEndpointOk(t *testing.T) {
+	db := &dependencyHealthTest{dependencyTest: dependencyTest{name: "db"}, healthy: true, policy: DEPENDENCY_POLICY_ABORT}
+	app := newHealthTestApp(t, db)
+
+	// Not ready before the application is started.
+	if status, body := healthTestRequest(t, app, "/readyz"); status != http.StatusServiceUnavailable || body.Meta == nil {
+		t.Errorf("expected status 503 with report before started, got %d", status)
+	}
+
+	app.ready.Store(true)
+	if status, _ := healthTestRequest(t, app, "/readyz"); status != http.StatusOK {
+		t.Errorf("expected status 200 when ready, got %d", status)
+	}
+
+	// Critical dependency is unhealthy.
+	db.healthy = false
+	status, body := healthTestRequest(t, app, "/readyz")
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", status)
+	}
+	if body.Error != "critical dependency unhealthy: db" {
+		t.Errorf("unexpected error %v", body.Error)
+	}
+}
@@ -3,15 +3,29 @@ package qore
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// HttpServerConfig defines the config for additional named HTTP(s) server.
+type HttpServerConfig struct {
+	// Port to listen.
+	Port int
+	// AutoTLS enables automatic TLS certificate from Let's Encrypt.
+	AutoTLS bool
+	// CertPath & KeyPath enables TLS with given certificate file.
+	CertPath string
+	KeyPath  string
+}
+
 type httpServer struct {
+	name            string
 	core            *echo.Echo
 	autoTLS         bool
 	certPath        string
@@ -19,21 +33,29 @@ type httpServer struct {
 	shutdownTimeout time.Duration
 	validator       HttpValidator
 	iApiResponse    ApiResponseInterface
+	routes          []httpRoute
+	codecs          []HttpCodec
+	errors          *errorRegistry
+	production      bool
 }
 
-func newHttpServer() *httpServer {
+func newHttpServer(name string) *httpServer {
 	core := echo.New()
 	core.HideBanner = true
 	core.HidePort = true
-	return &httpServer{
+	server := &httpServer{
+		name:         name,
 		core:         core,
 		validator:    httpValidatorDefault(),
 		iApiResponse: apiResponseInterfaceImpl{},
+		codecs:       httpCodecsDefault(),
 	}
+	core.Binder = &httpBinder{server: server}
+	return server
 }
 
 func (s *httpServer) start(lfn func() (listener net.Listener, err error), logger *logger) {
-	logger = logger.With(slog.String("scope", "http(s) server"))
+	logger = logger.With(slog.String("scope", "http(s) server"), slog.String("server", s.name))
 	if s.core == nil {
 		logger.Warn("http(s) server doe not initiated yet")
 		return
@@ -68,7 +90,7 @@ func (s *httpServer) stop(logger *logger) {
 	if s.core == nil {
 		return
 	}
-	logger = logger.With(slog.String("scope", "http(s) server"))
+	logger = logger.With(slog.String("scope", "http(s) server"), slog.String("server", s.name))
 
 	// Shutdown with context timeout.
 	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
@@ -82,3 +104,70 @@ func (s *httpServer) stop(logger *logger) {
 	}
 	logger.Debug("HTTP(S) server was stoped")
 }
+
+// AddHttpServer registers additional named HTTP(s) server with its own port, TLS, middleware,
+// validator and API response. It must be called before the application start.
+// The app-wide validation (see `RegisterValidation`) that is registered earlier is applied to the server.
+//
+//	app.AddHttpServer("internal", qore.HttpServerConfig{Port: 3200})
+//	app.SetHttpRoutesOn("internal", func(router *qore.HttpRouter) { ... })
+func (app *App) AddHttpServer(name string, config HttpServerConfig) error {
+	if ValidationIsEmpty(name) {
+		return errors.New("http(s) server name is required")
+	} else if config.Port <= 0 {
+		return fmt.Errorf("http(s) server %s port is invalid", name)
+	} else if _, exists := app.httpServers[name]; exists {
+		return fmt.Errorf("http(s) server %s already exists", name)
+	}
+
+	server := newHttpServer(name)
+	server.autoTLS = config.AutoTLS
+	server.certPath = config.CertPath
+	server.keyPath = config.KeyPath
+	server.shutdownTimeout = time.Duration(app.Config.ShutdownTimeout) * time.Second
+	server.core.Debug = !app.Config.AppProduction
+	server.production = app.Config.AppProduction
+	server.errors = &app.errorRegistry
+	if strings.EqualFold(app.Config.HTTPApiResponse, HTTP_API_RESPONSE_PROBLEM) {
+		server.iApiResponse = ApiResponseProblemInterface{TypeBase: app.Config.HTTPProblemTypeBase}
+	}
+	if v, ok := server.validator.(*httpValidatorImpl); ok && !ValidationIsEmpty(app.Config.HTTPValidatorLocale) {
+		v.locale = app.Config.HTTPValidatorLocale
+	}
+	if err := app.applyHttpValidatorSettings(server.validator); err != nil {
+		return fmt.Errorf("http(s) server %s: %w", name, err)
+	}
+	if app.httpServers == nil {
+		app.httpServers = make(map[string]*httpServer)
+	}
+	app.httpServers[name] = server
+	if name == HTTP_SERVER_DEFAULT {
+		app.httpServer = server
+	}
+	app.addServer("http."+name, fmt.Sprintf(":%d", config.Port), server)
+	return nil
+}
+
+// ServeHTTP serves the request by the default HTTP(s) server, so the app is usable as `http.Handler`,
+// e.g. testing the routes & middlewares with `httptest`.
+func (app *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server := app.httpServerByName(HTTP_SERVER_DEFAULT)
+	if server == nil {
+		http.NotFound(w, r)
+		return
+	}
+	server.core.ServeHTTP(w, r)
+}
+
+// httpServerByName returns registered HTTP(s) server by name, empty name returns the default server.
+func (app *App) httpServerByName(name string) *httpServer {
+	if ValidationIsEmpty(name) {
+		name = HTTP_SERVER_DEFAULT
+	}
+	server, ok := app.httpServers[name]
+	if !ok {
+		app.Logger().Debug(fmt.Sprintf("http(s) server %s is not registered", name))
+		return nil
+	}
+	return server
+}
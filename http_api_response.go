@@ -4,24 +4,98 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
-	"github.com/labstack/echo/v4"
+	"net/http"
+	"slices"
 )
 
 // ApiResponseDefault defines default API response object.
 type ApiResponseDefault struct {
-	Success bool   `json:"success" xml:"success"`
-	Code    string `json:"code" xml:"code"`
-	Data    any    `json:"data" xml:"data"`
-	Error   any    `json:"error" xml:"error"`
+	Success bool              `json:"success" xml:"success"`
+	Code    string            `json:"code" xml:"code"`
+	Message string            `json:"message,omitempty" xml:"message,omitempty"`
+	Data    any               `json:"data" xml:"data"`
+	Error   any               `json:"error" xml:"error"`
+	Meta    *ApiResponseMeta  `json:"meta,omitempty" xml:"meta,omitempty"`
+	Links   []ApiResponseLink `json:"links,omitempty" xml:"links>link,omitempty"`
+}
+
+// ApiResponseMeta defines metadata of `ApiResponseDefault`.
+type ApiResponseMeta struct {
+	Pagination *ApiResponsePagination `json:"pagination,omitempty" xml:"pagination,omitempty"`
+	// Additional is set by `ApiResponse.WithAdditional`, map is not supported by the XML output.
+	Additional any `json:"additional,omitempty" xml:"additional,omitempty"`
+}
+
+// ApiResponsePagination defines pagination metadata, use page & size for offset pagination
+// or next cursor for cursor pagination.
+type ApiResponsePagination struct {
+	Page       int    `json:"page,omitempty" xml:"page,omitempty"`
+	Size       int    `json:"size,omitempty" xml:"size,omitempty"`
+	Total      int64  `json:"total,omitempty" xml:"total,omitempty"`
+	TotalPages int64  `json:"totalPages,omitempty" xml:"totalPages,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+}
+
+// ApiResponseLink defines HATEOAS link of the resource.
+type ApiResponseLink struct {
+	Rel    string `json:"rel" xml:"rel,attr"`
+	Href   string `json:"href" xml:"href,attr"`
+	Method string `json:"method,omitempty" xml:"method,attr,omitempty"`
+}
+
+// NewApiResponsePagination returns offset pagination metadata with the calculated total pages.
+func NewApiResponsePagination(page, size int, total int64) ApiResponsePagination {
+	pagination := ApiResponsePagination{Page: page, Size: size, Total: total}
+	if size > 0 {
+		pagination.TotalPages = (total + int64(size) - 1) / int64(size)
+	}
+	return pagination
+}
+
+// NewApiResponseCursorPagination returns cursor pagination metadata.
+func NewApiResponseCursorPagination(size int, nextCursor string) ApiResponsePagination {
+	return ApiResponsePagination{Size: size, NextCursor: nextCursor}
 }
 
+// ApiResponseWithPagination returns given `ApiResponse` with pagination metadata when it implements
+// `ApiResponsePaginated`, otherwise returns it as is.
+//
+//	return qore.ApiResponseWithPagination(c.Api().Success(qore.HttpStatusOK, users),
+//		qore.NewApiResponsePagination(page, size, total)).Response()
+func ApiResponseWithPagination(r ApiResponse, pagination ApiResponsePagination) ApiResponse {
+	if paginated, ok := r.(ApiResponsePaginated); ok {
+		return paginated.WithPagination(pagination)
+	}
+	return r
+}
+
+// ApiResponseWithLinks returns given `ApiResponse` with HATEOAS links when it implements
+// `ApiResponsePaginated`, otherwise returns it as is.
+func ApiResponseWithLinks(r ApiResponse, links ...ApiResponseLink) ApiResponse {
+	if paginated, ok := r.(ApiResponsePaginated); ok {
+		return paginated.WithLinks(links...)
+	}
+	return r
+}
+
+// ApiResponseErrorDetail defines error payload of `ApiResponseDefault` for the validation error,
+// so the client is able to handle every invalid field at once.
+type ApiResponseErrorDetail struct {
+	Message string                  `json:"message" xml:"message"`
+	Fields  []HttpValidatorFieldErr `json:"fields" xml:"fields>field"`
+}
+
+func (r *ApiResponseDefault) codecPayload() any { return r.Data }
+
 type apiResponseInterfaceImpl struct{}
 
 type apiResponseImpl struct {
 	ctx    HttpContext
 	status int
 	object *ApiResponseDefault
+
+	// Problem details config, the error response is written as RFC 9457 problem details when it is set.
+	problem *ApiResponseProblemInterface
 }
 
 // Compile time check `apiResponseInterfaceImpl implements `ApiResponseInterface`.
@@ -30,6 +104,9 @@ var _ ApiResponseInterface = apiResponseInterfaceImpl{}
 // Compile time check `apiResponseImpl implements `ApiResponse`.
 var _ ApiResponse = (*apiResponseImpl)(nil)
 
+// Compile time check `apiResponseImpl implements `ApiResponsePaginated`.
+var _ ApiResponsePaginated = (*apiResponseImpl)(nil)
+
 // New creates `ApiResponse`.
 func (i apiResponseInterfaceImpl) New(c HttpContext) ApiResponse {
 	return &apiResponseImpl{ctx: c, object: new(ApiResponseDefault)}
@@ -50,28 +127,66 @@ func (r *apiResponseImpl) Success(status HttpResponseSuccess, data ...any) ApiRe
 
 // Error returns `ApiResponse` but should be check the error,
 // so it may return client or server error based on given error.
+//
+// Typed `Error` and sentinel error registered by `App.RegisterError` in the error chain return
+// its status & business code. The internal message of server error is hidden in production.
 func (r *apiResponseImpl) Error(err error) ApiResponse {
 	if err == nil {
 		return r.ServerError(HttpStatusNotImplemented, errors.New("failed: argument error is null"))
 	}
+	var (
+		registry   *errorRegistry
+		production bool
+	)
+	if ctx, ok := r.ctx.(*httpContextImpl); ok && ctx.server != nil {
+		registry, production = ctx.server.errors, ctx.server.production
+	}
+
+	// Domain error.
+	if qerr, ok := registry.resolve(err); ok {
+		msg := errors.New(qerr.Message)
+		if !production && qerr.Cause != nil {
+			msg = errors.New(qerr.Error())
+		}
+		switch {
+		case qerr.Status >= 400 && qerr.Status < 500:
+			r.ClientError(HttpResponseClientError(qerr.Status), msg)
+		case qerr.Status >= 500 && qerr.Status < 600:
+			r.ServerError(HttpResponseServerError(qerr.Status), msg)
+		default:
+			r.ServerError(HttpStatusInternalServerError, msg)
+		}
+		if !ValidationIsEmpty(qerr.Code) {
+			r.WithCode(qerr.Code)
+		}
+		return r
+	}
 
 	switch {
 	case errors.Is(err, context.DeadlineExceeded):
 		return r.ServerError(HttpStatusGatewayTimeout, errors.New("failed: Request timeout"))
 	case errors.Is(err, context.Canceled):
 		return r.ServerError(HttpStatusServiceUnavailable, errors.New("failed: Request canceled"))
+	case production:
+		return r.ServerError(HttpStatusInternalServerError, errors.New(http.StatusText(http.StatusInternalServerError)))
 	default:
 		return r.ServerError(HttpStatusInternalServerError, err)
 	}
 }
 
 // ClientError returns `ApiResponse` with HTTP(s) client error status (4xx).
+// The error that wraps `HttpValidatorErr` is written as `ApiResponseErrorDetail`.
 func (r *apiResponseImpl) ClientError(status HttpResponseClientError, err error) ApiResponse {
 	r.status = int(status)
 	r.object.Success = false
 	r.object.Code = fmt.Sprintf("%d", r.status)
 	if err != nil {
 		r.object.Error = err.Error()
+		var verr *HttpValidatorErr
+		if errors.As(err, &verr) && len(verr.Fields) > 0 {
+			// Copy the fields, the validator error may be released to the pool.
+			r.object.Error = &ApiResponseErrorDetail{Message: err.Error(), Fields: slices.Clone(verr.Fields)}
+		}
 	}
 	return r
 }
@@ -88,9 +203,12 @@ func (r *apiResponseImpl) ServerError(status HttpResponseServerError, err error)
 }
 
 // WithMessage returns `ApiResponse` with response message.
-func (r *apiResponseImpl) WithMessage(msg string) ApiResponse { return r }
+func (r *apiResponseImpl) WithMessage(msg string) ApiResponse {
+	r.object.Message = msg
+	return r
+}
 
-// WithMessage returns `ApiResponse` with response code.
+// WithCode returns `ApiResponse` with response code.
 func (r *apiResponseImpl) WithCode(code any) ApiResponse {
 	if code != nil {
 		r.object.Code = fmt.Sprintf("%v", code)
@@ -98,16 +216,50 @@ func (r *apiResponseImpl) WithCode(code any) ApiResponse {
 	return r
 }
 
-// WithMessage returns `ApiResponse` with additional data in the response.
-func (r *apiResponseImpl) WithAdditional(data any) ApiResponse { return r }
+// WithAdditional returns `ApiResponse` with additional data in the response meta.
+func (r *apiResponseImpl) WithAdditional(data any) ApiResponse {
+	r.meta().Additional = data
+	return r
+}
+
+// WithPagination returns `ApiResponse` with pagination in the response meta.
+func (r *apiResponseImpl) WithPagination(pagination ApiResponsePagination) ApiResponse {
+	r.meta().Pagination = &pagination
+	return r
+}
+
+// WithLinks returns `ApiResponse` with HATEOAS links of the resource.
+func (r *apiResponseImpl) WithLinks(links ...ApiResponseLink) ApiResponse {
+	r.object.Links = append(r.object.Links, links...)
+	return r
+}
 
-// Response write the `ApiResponse`. Returning error or nil.
+func (r *apiResponseImpl) meta() *ApiResponseMeta {
+	if r.object.Meta == nil {
+		r.object.Meta = new(ApiResponseMeta)
+	}
+	return r.object.Meta
+}
+
+// Response write the `ApiResponse` encoded by the codec negotiated from the `Accept` request header.
+// When no codec is acceptable the success response is replaced by `406 Not Acceptable` and the error
+// response is kept, both of them are written in JSON. Returning error or nil.
+// The error response of `ApiResponseProblemInterface` is written as RFC 9457 problem details.
 func (r *apiResponseImpl) Response() error {
-	accept := r.ctx.Request().Header.Get("Accept")
-	switch accept {
-	case echo.MIMETextXML, echo.MIMEApplicationXML, echo.MIMEApplicationXMLCharsetUTF8:
-		return r.ctx.XML(r.status, r.object)
-	default:
+	if r.problem != nil && !r.object.Success {
+		return r.problemResponse()
+	}
+	negotiator, ok := r.ctx.(httpNegotiator)
+	if !ok {
+		return r.ctx.JSON(r.status, r.object)
+	}
+	err := negotiator.negotiate(r.status, r.object)
+	if errors.Is(err, ErrHttpNotAcceptable) {
+		if r.object.Success {
+			r.object.Data = nil
+			r.ClientError(HttpStatusNotAcceptable, err)
+		}
 		return r.ctx.JSON(r.status, r.object)
 	}
+	return err
 }
@@ -0,0 +1,124 @@
+package qore
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ApiResponseProblem defines RFC 9457 problem details object, https://www.rfc-editor.org/rfc/rfc9457.
+type ApiResponseProblem struct {
+	XMLName  xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type     string   `json:"type" xml:"type"`
+	Title    string   `json:"title" xml:"title"`
+	Status   int      `json:"status" xml:"status"`
+	Detail   string   `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string   `json:"instance,omitempty" xml:"instance,omitempty"`
+
+	// Extension members.
+	Code       string                  `json:"code,omitempty" xml:"code,omitempty"`
+	Errors     []HttpValidatorFieldErr `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	Additional any                     `json:"additional,omitempty" xml:"additional,omitempty"`
+}
+
+// ApiResponseProblemInterface implements `ApiResponseInterface` that writes the error response as
+// RFC 9457 problem details (`application/problem+json` or `application/problem+xml`),
+// the success response is still written as `ApiResponseDefault`.
+//
+//	app.SetApiResponseInterface(qore.ApiResponseProblemInterface{TypeBase: "https://example.com/problems/"})
+type ApiResponseProblemInterface struct {
+	// TypeBase is base URI of the problem type, the type is the base URI followed by the problem slug,
+	// e.g. `https://example.com/problems/validation-error`. Empty base URI uses `about:blank` type.
+	TypeBase string
+}
+
+// Compile time check `ApiResponseProblemInterface implements `ApiResponseInterface`.
+var _ ApiResponseInterface = ApiResponseProblemInterface{}
+
+// New creates `ApiResponse`.
+func (i ApiResponseProblemInterface) New(c HttpContext) ApiResponse {
+	return &apiResponseImpl{ctx: c, object: new(ApiResponseDefault), problem: &i}
+}
+
+// problemType returns type URI of the problem slug.
+func (i ApiResponseProblemInterface) problemType(slug string) string {
+	if ValidationIsEmpty(i.TypeBase) {
+		return "about:blank"
+	}
+	return strings.TrimSuffix(i.TypeBase, "/") + "/" + slug
+}
+
+// problemSlug returns lower case slug of the problem title, e.g. `Bad Request` is `bad-request`.
+func problemSlug(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}), "-")
+}
+
+// problemResponse writes the error response as problem details, it is written as XML only
+// when XML is preferred over JSON by the `Accept` request header.
+func (r *apiResponseImpl) problemResponse() error {
+	title := http.StatusText(r.status)
+	problem := &ApiResponseProblem{
+		Type:     r.problem.problemType(problemSlug(title)),
+		Title:    title,
+		Status:   r.status,
+		Instance: r.ctx.TraceID(),
+	}
+	if r.object.Code != fmt.Sprintf("%d", r.status) {
+		problem.Code = r.object.Code
+	}
+	switch e := r.object.Error.(type) {
+	case *ApiResponseErrorDetail:
+		problem.Type = r.problem.problemType("validation-error")
+		problem.Detail = e.Message
+		problem.Errors = e.Fields
+	case string:
+		problem.Detail = e
+	}
+	if !ValidationIsEmpty(r.object.Message) {
+		problem.Detail = r.object.Message
+	}
+	if r.object.Meta != nil {
+		problem.Additional = r.object.Meta.Additional
+	}
+
+	// Problem details of XML, it falls back to JSON when XML can not encode the problem (e.g. map of additional).
+	if problemPreferXML(r.ctx.Request().Header.Get(echo.HeaderAccept)) {
+		if data, err := xml.Marshal(problem); err == nil {
+			return r.ctx.Blob(r.status, HTTP_MIME_PROBLEM_XML, append([]byte(xml.Header), data...))
+		}
+	}
+	data, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	return r.ctx.Blob(r.status, HTTP_MIME_PROBLEM_JSON, data)
+}
+
+// problemPreferXML returns true if `application/problem+xml` or `application/xml` ranks above JSON
+// by the `Accept` header, JSON is preferred on the same quality and the wildcard.
+func problemPreferXML(accept string) bool {
+	ranges := httpAcceptRanges(accept)
+	for i := 0; i < len(ranges); {
+		var xmlAccepted bool
+		j := i
+		for ; j < len(ranges) && ranges[j].quality == ranges[i].quality; j++ {
+			switch ranges[j].value {
+			case HTTP_MIME_PROBLEM_JSON, echo.MIMEApplicationJSON, "application/*", "*/*":
+				return false
+			case HTTP_MIME_PROBLEM_XML, echo.MIMEApplicationXML:
+				xmlAccepted = true
+			}
+		}
+		if xmlAccepted {
+			return true
+		}
+		i = j
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package qore
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestApiResponseProblemOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	server.iApiResponse = ApiResponseProblemInterface{TypeBase: "https://example.com/problems/"}
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(HTTP_CONTEXT_TRACE_ID, "trace-1")
+			return next(c)
+		}
+	})
+	server.core.POST("/users", httpHandlerToEchoHandler(HttpHanlderChainWithPayload(
+		func(c HttpContext, p *validatorPayloadTest) error { return c.Api().Success(HttpStatusOK).Response() },
+	).HandlerWrapper, server, logger))
+	server.core.GET("/users", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().ServerError(HttpStatusInternalServerError, errors.New("database is down")).WithCode("DB_DOWN").Response()
+	}, server, logger))
+
+	// Validation failure.
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"age":20}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	server.core.ServeHTTP(rec, req)
+	var problem ApiResponseProblem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || rec.Header().Get(echo.HeaderContentType) != HTTP_MIME_PROBLEM_JSON {
+		t.Errorf("unexpected response %d %s", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	if problem.Type != "https://example.com/problems/validation-error" || problem.Status != http.StatusBadRequest ||
+		problem.Instance != "trace-1" || len(problem.Errors) != 2 {
+		t.Errorf("unexpected problem %+v", problem)
+	}
+
+	// Server error in XML.
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(echo.HeaderAccept, "application/problem+xml, application/problem+json;q=0.5")
+	rec = httptest.NewRecorder()
+	server.core.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get(echo.HeaderContentType) != HTTP_MIME_PROBLEM_XML {
+		t.Errorf("unexpected response %d %s", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	for _, expected := range []string{
+		`<problem xmlns="urn:ietf:rfc:7807">`,
+		`<type>https://example.com/problems/internal-server-error</type>`,
+		`<detail>database is down</detail>`,
+		`<code>DB_DOWN</code>`,
+	} {
+		if !strings.Contains(rec.Body.String(), expected) {
+			t.Errorf("expected %s in %s", expected, rec.Body.String())
+		}
+	}
+}
+
+func TestApiResponseProblemNegotiationOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	server.iApiResponse = ApiResponseProblemInterface{}
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().ClientError(HttpStatusNotFound, errors.New("user is not found")).Response()
+	}, server, logger))
+	server.core.GET("/orders", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().ClientError(HttpStatusNotFound, errors.New("order is not found")).
+			WithAdditional(map[string]any{"orderId": 7}).Response()
+	}, server, logger))
+
+	tests := []struct {
+		path        string
+		accept      string
+		contentType string
+	}{
+		{"/users", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", HTTP_MIME_PROBLEM_XML},
+		{"/users", "text/html,application/xhtml+xml", HTTP_MIME_PROBLEM_JSON},
+		{"/users", "application/xml, application/json", HTTP_MIME_PROBLEM_JSON},
+		{"/users", "application/xml, */*", HTTP_MIME_PROBLEM_JSON},
+		{"/users", "application/json;q=0.5, application/problem+xml", HTTP_MIME_PROBLEM_XML},
+		{"/users", "text/xml", HTTP_MIME_PROBLEM_JSON},
+		// XML can not encode the map of additional.
+		{"/orders", "application/problem+xml", HTTP_MIME_PROBLEM_JSON},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.Header.Set(echo.HeaderAccept, test.accept)
+		rec := httptest.NewRecorder()
+		server.core.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotFound || rec.Header().Get(echo.HeaderContentType) != test.contentType {
+			t.Errorf("%s %q: unexpected %d %s", test.path, test.accept, rec.Code, rec.Header().Get(echo.HeaderContentType))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package qore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestApiResponseMetaOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users", httpHandlerToEchoHandler(func(c HttpContext) error {
+		response := c.Api().Success(HttpStatusOK, []string{"a", "b"}).WithMessage("users found")
+		response = ApiResponseWithPagination(response, NewApiResponsePagination(2, 2, 5))
+		response = ApiResponseWithLinks(response, ApiResponseLink{Rel: "next", Href: "/users?page=3"})
+		return response.WithAdditional(struct {
+			Region string `json:"region" xml:"region"`
+		}{Region: "id"}).Response()
+	}, server, logger))
+
+	tests := []struct {
+		accept   string
+		expected []string
+	}{
+		{accept: echo.MIMEApplicationJSON, expected: []string{
+			`"message":"users found"`,
+			`"pagination":{"page":2,"size":2,"total":5,"totalPages":3}`,
+			`"additional":{"region":"id"}`,
+			`"links":[{"rel":"next","href":"/users?page=3"}]`,
+		}},
+		{accept: echo.MIMEApplicationXML, expected: []string{
+			`<message>users found</message>`,
+			`<pagination><page>2</page><size>2</size><total>5</total><totalPages>3</totalPages></pagination>`,
+			`<additional><region>id</region></additional>`,
+			`<links><link rel="next" href="/users?page=3"></link></links>`,
+		}},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set(echo.HeaderAccept, test.accept)
+		rec := httptest.NewRecorder()
+		server.core.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(rec.Body.String(), expected) {
+				t.Errorf("%s: expected %s in %s", test.accept, expected, rec.Body.String())
+			}
+		}
+	}
+}
+
+// apiResponseCustomTest is custom `ApiResponse` that does not implement `ApiResponsePaginated`.
+type apiResponseCustomTest struct {
+	ApiResponse
+}
+
+func TestApiResponsePaginationOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return ApiResponseWithPagination(c.Api().Success(HttpStatusOK, []string{"a"}),
+			NewApiResponseCursorPagination(1, "b")).Response()
+	}, server, logger))
+
+	rec := httptest.NewRecorder()
+	server.core.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if expected := `"pagination":{"size":1,"nextCursor":"b"}`; !strings.Contains(rec.Body.String(), expected) {
+		t.Errorf("expected %s in %s", expected, rec.Body.String())
+	}
+
+	custom := apiResponseCustomTest{}
+	if r := ApiResponseWithPagination(custom, NewApiResponsePagination(1, 1, 1)); r != custom {
+		t.Errorf("expected custom response is returned as is, got %v", r)
+	}
+	if r := ApiResponseWithLinks(custom, ApiResponseLink{Rel: "self", Href: "/users"}); r != custom {
+		t.Errorf("expected custom response is returned as is, got %v", r)
+	}
+}
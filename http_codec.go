@@ -0,0 +1,306 @@
+package qore
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// HttpCodec defines encoder & decoder of HTTP(s) body for the media types.
+type HttpCodec interface {
+	// MediaTypes returns supported media types, the first media type is used as response content type.
+	MediaTypes() []string
+	// Marshal encodes v, returns `ErrHttpCodecUnsupported` when v can not be encoded by the codec.
+	Marshal(v any) ([]byte, error)
+	// Unmarshal decodes data into v.
+	Unmarshal(data []byte, v any) error
+}
+
+// httpCodecPayload is implemented by response envelope that wraps the data,
+// so the codec that only supports specific type (e.g. protobuf) is able to encode the data.
+type httpCodecPayload interface {
+	codecPayload() any
+}
+
+type httpCodecJSON struct{}
+
+func (httpCodecJSON) MediaTypes() []string { return []string{echo.MIMEApplicationJSON} }
+
+func (httpCodecJSON) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (httpCodecJSON) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type httpCodecXML struct{}
+
+func (httpCodecXML) MediaTypes() []string {
+	return []string{echo.MIMEApplicationXML, echo.MIMETextXML}
+}
+
+// Marshal encodes v into XML, v that can not be encoded (e.g. map) is unsupported
+// so the negotiation continues to the next codec.
+func (httpCodecXML) Marshal(v any) ([]byte, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHttpCodecUnsupported, err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func (httpCodecXML) Unmarshal(data []byte, v any) error { return xml.Unmarshal(data, v) }
+
+type httpCodecMsgpack struct{}
+
+func (httpCodecMsgpack) MediaTypes() []string {
+	return []string{HTTP_MIME_MSGPACK, "application/x-msgpack", "application/vnd.msgpack"}
+}
+
+func (httpCodecMsgpack) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (httpCodecMsgpack) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+type httpCodecCBOR struct{}
+
+func (httpCodecCBOR) MediaTypes() []string { return []string{HTTP_MIME_CBOR} }
+
+func (httpCodecCBOR) Marshal(v any) ([]byte, error) { return cbor.Marshal(v) }
+
+func (httpCodecCBOR) Unmarshal(data []byte, v any) error { return cbor.Unmarshal(data, v) }
+
+type httpCodecProtobuf struct{}
+
+func (httpCodecProtobuf) MediaTypes() []string {
+	return []string{HTTP_MIME_PROTOBUF, "application/x-protobuf"}
+}
+
+// Marshal encodes proto message, the response envelope is unwrapped into its data.
+func (httpCodecProtobuf) Marshal(v any) ([]byte, error) {
+	if payload, ok := v.(httpCodecPayload); ok {
+		v = payload.codecPayload()
+	}
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, ErrHttpCodecUnsupported
+	}
+	return proto.Marshal(msg)
+}
+
+func (httpCodecProtobuf) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return ErrHttpCodecUnsupported
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// httpCodecsDefault returns built-in codecs, JSON is the default codec.
+func httpCodecsDefault() []HttpCodec {
+	return []HttpCodec{httpCodecJSON{}, httpCodecXML{}, httpCodecMsgpack{}, httpCodecCBOR{}, httpCodecProtobuf{}}
+}
+
+// httpAcceptRange defines a value of header with quality value.
+type httpAcceptRange struct {
+	value   string
+	quality float64
+}
+
+// httpAcceptRanges parses header with quality value (e.g. `Accept` or `Accept-Language`)
+// into values ordered by the quality value, the value with zero quality is excluded.
+func httpAcceptRanges(header string) []httpAcceptRange {
+	var ranges []httpAcceptRange
+	for part := range strings.SplitSeq(header, ",") {
+		val, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		val = strings.ToLower(strings.TrimSpace(val))
+		if val == "" {
+			continue
+		}
+		quality := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if n, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = n
+				}
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, httpAcceptRange{value: val, quality: quality})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b httpAcceptRange) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		}
+		return 0
+	})
+	return ranges
+}
+
+// httpAcceptValues parses header with quality value into values ordered by the quality value.
+func httpAcceptValues(header string) []string {
+	ranges := httpAcceptRanges(header)
+	result := make([]string, len(ranges))
+	for i, r := range ranges {
+		result[i] = r.value
+	}
+	return result
+}
+
+// httpMediaTypeMatch returns true if the media type matches the accepted media range,
+// e.g. `application/*` or `*/*`.
+func httpMediaTypeMatch(accepted, mediaType string) bool {
+	if accepted == "*/*" || accepted == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(accepted, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// httpCodecByContentType returns codec of the request content type.
+func httpCodecByContentType(codecs []HttpCodec, contentType string) HttpCodec {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, codec := range codecs {
+		if slices.Contains(codec.MediaTypes(), mediaType) {
+			return codec
+		}
+	}
+	return nil
+}
+
+// httpNegotiation defines a codec candidate of the negotiation.
+type httpNegotiation struct {
+	codec     HttpCodec
+	mediaType string
+}
+
+// httpNegotiate encodes v with the codec that matches the `Accept` header with the highest quality and supports v.
+// The media type matches the more specific range first, the default (first) codec is preferred among
+// the media types of the same quality & specificity. Empty `Accept` header is encoded by the default codec.
+func httpNegotiate(codecs []HttpCodec, accept string, v any) (mediaType string, body []byte, err error) {
+	ranges := httpAcceptRanges(accept)
+	if ValidationIsEmpty(strings.TrimSpace(accept)) {
+		ranges = []httpAcceptRange{{value: "*/*", quality: 1}}
+	}
+	for i := 0; i < len(ranges); {
+		// Candidates of the same quality, the exact media type is followed by the wildcard.
+		var exact, wildcard []httpNegotiation
+		j := i
+		for ; j < len(ranges) && ranges[j].quality == ranges[i].quality; j++ {
+			for k, codec := range codecs {
+				for _, mt := range codec.MediaTypes() {
+					if !httpMediaTypeMatch(ranges[j].value, mt) {
+						continue
+					}
+					candidates := &exact
+					if strings.HasSuffix(ranges[j].value, "/*") {
+						candidates = &wildcard
+					}
+					if k == 0 {
+						*candidates = slices.Insert(*candidates, 0, httpNegotiation{codec: codec, mediaType: mt})
+					} else {
+						*candidates = append(*candidates, httpNegotiation{codec: codec, mediaType: mt})
+					}
+					break
+				}
+			}
+		}
+		i = j
+
+		for _, candidate := range append(exact, wildcard...) {
+			body, err = candidate.codec.Marshal(v)
+			if errors.Is(err, ErrHttpCodecUnsupported) {
+				continue
+			} else if err != nil {
+				return "", nil, err
+			}
+			return candidate.mediaType, body, nil
+		}
+	}
+	return "", nil, ErrHttpNotAcceptable
+}
+
+// httpBinder is echo binder that decodes request body by the registered codecs,
+// JSON, XML & form body are still decoded by the echo default binder.
+type httpBinder struct {
+	echo.DefaultBinder
+	server *httpServer
+}
+
+func (b *httpBinder) Bind(i any, c echo.Context) error {
+	codec := httpCodecByContentType(b.server.codecs, c.Request().Header.Get(echo.HeaderContentType))
+	switch codec.(type) {
+	case nil, httpCodecJSON, httpCodecXML:
+		return b.DefaultBinder.Bind(i, c)
+	}
+
+	if err := b.BindPathParams(c, i); err != nil {
+		return err
+	}
+	method := c.Request().Method
+	if method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead {
+		if err := b.BindQueryParams(c, i); err != nil {
+			return err
+		}
+	}
+	if c.Request().ContentLength == 0 {
+		return nil
+	}
+	data, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err := codec.Unmarshal(data, i); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+// SetHttpCodec registers codec into the default HTTP(s) server, the codec replaces registered codec
+// of the same first media type. Registered codec is used for response content negotiation and
+// request payload decoding.
+func (app *App) SetHttpCodec(codec HttpCodec) {
+	app.SetHttpCodecOn(HTTP_SERVER_DEFAULT, codec)
+}
+
+// SetHttpCodecOn registers codec into the named HTTP(s) server.
+func (app *App) SetHttpCodecOn(name string, codec HttpCodec) {
+	server := app.httpServerByName(name)
+	if server == nil {
+		return
+	} else if codec == nil || len(codec.MediaTypes()) == 0 {
+		return
+	}
+	for i, registered := range server.codecs {
+		if registered.MediaTypes()[0] == codec.MediaTypes()[0] {
+			server.codecs[i] = codec
+			return
+		}
+	}
+	server.codecs = append(server.codecs, codec)
+}
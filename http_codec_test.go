@@ -0,0 +1,167 @@
+package qore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type codecPayloadTest struct {
+	ID   int    `json:"id" param:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+func (p *codecPayloadTest) Validate() error { return nil }
+
+func serveCodecTest(server *httpServer, method, accept, contentType string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/users/7", bytes.NewReader(body))
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+	rec := httptest.NewRecorder()
+	server.core.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHttpCodecNegotiationOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users/:id", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().Success(HttpStatusOK, codecPayloadTest{ID: 7, Name: "qore"}).Response()
+	}, server, logger))
+
+	tests := []struct {
+		accept      string
+		status      int
+		contentType string
+	}{
+		{accept: "", status: http.StatusOK, contentType: echo.MIMEApplicationJSON},
+		{accept: "application/xml;q=0.5, application/msgpack", status: http.StatusOK, contentType: HTTP_MIME_MSGPACK},
+		{accept: "text/html, application/*;q=0.8", status: http.StatusOK, contentType: echo.MIMEApplicationJSON},
+		{accept: "text/xml", status: http.StatusOK, contentType: echo.MIMETextXML},
+		{accept: "application/cbor", status: http.StatusOK, contentType: HTTP_MIME_CBOR},
+		{accept: "application/protobuf", status: http.StatusNotAcceptable, contentType: echo.MIMEApplicationJSON},
+		{accept: "text/html, application/json;q=0", status: http.StatusNotAcceptable, contentType: echo.MIMEApplicationJSON},
+	}
+	for _, test := range tests {
+		rec := serveCodecTest(server, http.MethodGet, test.accept, "", nil)
+		if rec.Code != test.status || rec.Header().Get(echo.HeaderContentType) != test.contentType {
+			t.Errorf("%q: unexpected %d %s", test.accept, rec.Code, rec.Header().Get(echo.HeaderContentType))
+		}
+	}
+
+	// Msgpack body uses the JSON field name.
+	rec := serveCodecTest(server, http.MethodGet, HTTP_MIME_MSGPACK, "", nil)
+	var response ApiResponseDefault
+	if err := (httpCodecMsgpack{}).Unmarshal(rec.Body.Bytes(), &response); err != nil || !response.Success {
+		t.Errorf("unexpected msgpack body %v: %+v", err, response)
+	}
+}
+
+func TestHttpCodecDecodingOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	chain := HttpHanlderChainWithPayloadAndResult(func(c HttpContext, p *codecPayloadTest) (*codecPayloadTest, error) {
+		return p, nil
+	})
+	server.core.POST("/users/:id", httpHandlerToEchoHandler(chain.HandlerWrapper, server, logger))
+
+	body, _ := cbor.Marshal(map[string]any{"name": "qore"})
+	rec := serveCodecTest(server, http.MethodPost, HTTP_MIME_CBOR, HTTP_MIME_CBOR, body)
+	var response struct {
+		Data codecPayloadTest `json:"data"`
+	}
+	if err := cbor.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || response.Data.ID != 7 || response.Data.Name != "qore" {
+		t.Errorf("unexpected response %d %+v", rec.Code, response)
+	}
+
+	// Invalid body of registered codec.
+	rec = serveCodecTest(server, http.MethodPost, "", HTTP_MIME_MSGPACK, []byte{0xc1})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", rec.Code)
+	}
+}
+
+func TestHttpCodecProtobufOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users/:id", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().Success(HttpStatusOK, wrapperspb.String("qore")).Response()
+	}, server, logger))
+
+	rec := serveCodecTest(server, http.MethodGet, HTTP_MIME_PROTOBUF, "", nil)
+	var msg wrapperspb.StringValue
+	if err := proto.Unmarshal(rec.Body.Bytes(), &msg); err != nil || msg.GetValue() != "qore" {
+		t.Errorf("unexpected protobuf body %v: %v", err, msg.GetValue())
+	}
+}
+
+func TestHttpCodecNegotiationPreferenceOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users/:id", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().Success(HttpStatusOK, codecPayloadTest{ID: 7, Name: "qore"}).Response()
+	}, server, logger))
+	server.core.GET("/maps/:id", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().Success(HttpStatusOK, map[string]any{"id": 7}).Response()
+	}, server, logger))
+
+	browser := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	tests := []struct {
+		path        string
+		accept      string
+		contentType string
+	}{
+		// XML can not encode the map, so it is continued to the wildcard.
+		{"/maps/7", browser, echo.MIMEApplicationJSON},
+		{"/users/7", browser, echo.MIMEApplicationXML},
+		{"/users/7", "*/*", echo.MIMEApplicationJSON},
+		{"/users/7", "application/*", echo.MIMEApplicationJSON},
+		{"/users/7", "application/msgpack, application/json", echo.MIMEApplicationJSON},
+		{"/users/7", "application/xml, */*", echo.MIMEApplicationXML},
+		{"/users/7", "application/cbor, application/msgpack", HTTP_MIME_CBOR},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.Header.Set(echo.HeaderAccept, test.accept)
+		rec := httptest.NewRecorder()
+		server.core.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Header().Get(echo.HeaderContentType) != test.contentType {
+			t.Errorf("%s %q: unexpected %d %s", test.path, test.accept, rec.Code, rec.Header().Get(echo.HeaderContentType))
+		}
+	}
+}
+
+func TestHttpCodecErrorFallbackOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.GET("/users/:id", httpHandlerToEchoHandler(func(c HttpContext) error {
+		return c.Api().ClientError(HttpStatusNotFound, errors.New("user is not found")).Response()
+	}, server, logger))
+
+	rec := serveCodecTest(server, http.MethodGet, HTTP_MIME_PROTOBUF, "", nil)
+	if rec.Code != http.StatusNotFound || rec.Header().Get(echo.HeaderContentType) != echo.MIMEApplicationJSON {
+		t.Fatalf("unexpected %d %s", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	var response ApiResponseDefault
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Success || response.Error != "user is not found" {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
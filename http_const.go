@@ -1,10 +1,68 @@
 package qore
 
 const (
+	// Name of the default HTTP(s) server.
+	HTTP_SERVER_DEFAULT = "default"
+	// Name of the admin HTTP(s) server that is created from `Config.HTTPAdminPort`.
+	HTTP_SERVER_ADMIN = "admin"
 	// HTTP header key for trace id.
 	HTTP_HEADER_TRACE_ID = "X-Trace-ID"
+	// HTTP header key for accepted language of the response message.
+	HTTP_HEADER_ACCEPT_LANGUAGE = "Accept-Language"
+	// HTTP header key for the seconds to wait before making a new request.
+	HTTP_HEADER_RETRY_AFTER = "Retry-After"
 	// HTTP context key for trace id
 	HTTP_CONTEXT_TRACE_ID = "traceId"
 	// HTTP context key for auth session.
 	HTTP_CONTEXT_AUTH = "auth"
 )
+
+// HTTP header key of the rate limit, see IETF draft `RateLimit header fields for HTTP`.
+const (
+	HTTP_HEADER_RATE_LIMIT_LIMIT     = "RateLimit-Limit"
+	HTTP_HEADER_RATE_LIMIT_REMAINING = "RateLimit-Remaining"
+	HTTP_HEADER_RATE_LIMIT_RESET     = "RateLimit-Reset"
+	HTTP_HEADER_RATE_LIMIT_POLICY    = "RateLimit-Policy"
+)
+
+// Built-in locale of HTTP(s) validator messages.
+const (
+	HTTP_VALIDATOR_LOCALE_EN = "en"
+	HTTP_VALIDATOR_LOCALE_ID = "id"
+)
+
+// Built-in validation tag of the default HTTP(s) validator.
+const (
+	// HTTP_VALIDATION_ID_PHONE validates Indonesian mobile phone number, e.g. `+6281234567890` or `081234567890`.
+	HTTP_VALIDATION_ID_PHONE = "id_phone"
+	// HTTP_VALIDATION_NIK validates Indonesian NIK (Nomor Induk Kependudukan).
+	HTTP_VALIDATION_NIK = "nik"
+	// HTTP_VALIDATION_SLUG validates lower case URL slug, e.g. `hello-world`.
+	HTTP_VALIDATION_SLUG = "slug"
+	// HTTP_VALIDATION_STRONG_PASSWORD validates password of at least 8 characters with upper case,
+	// lower case, number and symbol.
+	HTTP_VALIDATION_STRONG_PASSWORD = "strong_password"
+	// HTTP_VALIDATION_UUID7 validates UUID version 7, ULID is validated by the `ulid` tag of the validator.
+	HTTP_VALIDATION_UUID7 = "uuid7"
+)
+
+// MIME type of the built-in HTTP(s) codecs.
+const (
+	HTTP_MIME_MSGPACK  = "application/msgpack"
+	HTTP_MIME_CBOR     = "application/cbor"
+	HTTP_MIME_PROTOBUF = "application/protobuf"
+)
+
+// MIME type of RFC 9457 problem details.
+const (
+	HTTP_MIME_PROBLEM_JSON = "application/problem+json"
+	HTTP_MIME_PROBLEM_XML  = "application/problem+xml"
+)
+
+// Enum of the built-in `ApiResponseInterface` that is selected by `Config.HTTPApiResponse`.
+const (
+	// HTTP_API_RESPONSE_DEFAULT writes every response as `ApiResponseDefault`.
+	HTTP_API_RESPONSE_DEFAULT = "DEFAULT"
+	// HTTP_API_RESPONSE_PROBLEM writes the error response as RFC 9457 problem details.
+	HTTP_API_RESPONSE_PROBLEM = "PROBLEM"
+)
@@ -14,11 +14,15 @@ type httpContextImpl struct {
 	logger *logger
 }
 
-// ValidateRequest.
+// ValidateRequest, the error message is localized by the `Accept-Language` request header
+// when the validator implements `HttpValidatorLocalized`.
 func (h *httpContextImpl) ValidateRequest(i any) *HttpValidatorErr {
 	if h.server.validator == nil {
 		return &HttpValidatorErr{ErrMandatory: ErrHttpValidatorNotRegistered}
 	}
+	if localized, ok := h.server.validator.(HttpValidatorLocalized); ok {
+		return localized.ValidateLocalized(i, h.Request().Header.Get(HTTP_HEADER_ACCEPT_LANGUAGE))
+	}
 	return h.server.validator.Validate(i)
 }
 
@@ -40,6 +44,22 @@ func (h *httpContextImpl) Log() *logger {
 	return h.logger.With("traceId", traceID)
 }
 
+// httpNegotiator is implemented by the HTTP context that writes the response by the registered codecs.
+type httpNegotiator interface {
+	negotiate(status int, v any) error
+}
+
+// negotiate writes v with the status, v is encoded by the registered codec negotiated from
+// the `Accept` request header. It returns `ErrHttpNotAcceptable` when no codec is acceptable.
+func (h *httpContextImpl) negotiate(status int, v any) error {
+	mediaType, body, err := httpNegotiate(h.server.codecs, h.Request().Header.Get(echo.HeaderAccept), v)
+	if err != nil {
+		return err
+	}
+	h.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	return h.Blob(status, mediaType, body)
+}
+
 // Api return HTTP(s) API responder.
 func (h *httpContextImpl) Api() ApiResponse {
 	return h.server.iApiResponse.New(h)
@@ -4,4 +4,6 @@ import "errors"
 
 var (
 	ErrHttpValidatorNotRegistered = errors.New("validator not registered")
+	ErrHttpCodecUnsupported       = errors.New("codec does not support the value")
+	ErrHttpNotAcceptable          = errors.New("none of the accepted media types is supported")
 )
@@ -4,10 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 )
 
 type httpHandlerChainImpl[T HttpRequestPayload] struct {
-	requestPayload     T
+	newPayload         func() T
 	handler            HttpHandlerable
 	handlerWithPayload HttpHandlerableWithPayload[T]
 }
@@ -21,9 +22,69 @@ func HttpHanlderChain(h HttpHandlerable) *httpHandlerChainImpl[HttpRequestPayloa
 }
 
 // HttpHanlderChainWithPayload returns `HttpHandlerChain` that wrap user's handler using given payload type.
+// Every request gets a fresh payload, pointer payload type is allocated via reflection.
 func HttpHanlderChainWithPayload[T HttpRequestPayload](h HttpHandlerableWithPayload[T]) *httpHandlerChainImpl[T] {
-	var zero T
-	return &httpHandlerChainImpl[T]{requestPayload: zero, handlerWithPayload: h}
+	return &httpHandlerChainImpl[T]{newPayload: httpRequestPayloadAllocator[T](), handlerWithPayload: h}
+}
+
+// HttpHanlderChainWithPayloadFunc same like `HttpHanlderChainWithPayload` but the payload
+// of every request is created by given constructor.
+func HttpHanlderChainWithPayloadFunc[T HttpRequestPayload](newPayload func() T, h HttpHandlerableWithPayload[T]) *httpHandlerChainImpl[T] {
+	if newPayload == nil {
+		newPayload = httpRequestPayloadAllocator[T]()
+	}
+	return &httpHandlerChainImpl[T]{newPayload: newPayload, handlerWithPayload: h}
+}
+
+// httpRequestPayloadAllocator returns function that creates a fresh payload of type T.
+// Pointer type is allocated to the new zero value of its element, while interface type
+// can not be allocated so the function returns nil.
+func httpRequestPayloadAllocator[T HttpRequestPayload]() func() T {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Pointer {
+		elem := typ.Elem()
+		return func() T { return reflect.New(elem).Interface().(T) }
+	}
+	return func() T {
+		var zero T
+		return zero
+	}
+}
+
+// bindPayload creates a fresh payload for the current request then binds & validates it.
+// It returns the payload and the error response that has been written if any.
+func bindPayload[T HttpRequestPayload](c HttpContext, newPayload func() T) (payload T, handled bool, err error) {
+	if newPayload != nil {
+		payload = newPayload()
+	}
+	if any(payload) == nil {
+		return payload, true, c.Api().ServerError(
+			HttpStatusNotImplemented, errors.New("error Malfunction on wrap up the payload"),
+		).Response()
+	}
+
+	// Do binding data, pointer payload is bound directly.
+	target := any(&payload)
+	if reflect.TypeOf(payload).Kind() == reflect.Pointer {
+		target = payload
+	}
+	if err := c.Bind(target); err != nil {
+		return payload, true, c.Api().ClientError(
+			HttpStatusBadRequest, errors.New("error Invalid formating on request payload"),
+		).Response()
+	}
+	// Global validation on request payload.
+	if err := c.ValidateRequest(payload); err != nil {
+		defer err.Close()
+		e := fmt.Errorf("%s, %w", http.StatusText(int(HttpStatusBadRequest)), err)
+		return payload, true, c.Api().ClientError(HttpStatusBadRequest, e).Response()
+	}
+	// Custom validation from user on request payload.
+	if err := payload.Validate(); err != nil {
+		e := fmt.Errorf("%s, %w", http.StatusText(int(HttpStatusBadRequest)), err)
+		return payload, true, c.Api().ClientError(HttpStatusBadRequest, e).Response()
+	}
+	return payload, false, nil
 }
 
 func (chain *httpHandlerChainImpl[T]) HandlerWrapper(c HttpContext) error {
@@ -39,29 +100,97 @@ func (chain *httpHandlerChainImpl[T]) HandlerWrapper(c HttpContext) error {
 		return chain.handler(c)
 	}
 
-	// Then, handler with payload.
-	if any(chain.requestPayload) == nil {
+	// Then, handler with payload using a fresh payload per request.
+	payload, handled, err := bindPayload(c, chain.newPayload)
+	if handled {
+		return err
+	}
+	return chain.handlerWithPayload(c, payload)
+}
+
+type httpHandlerChainResultImpl[Req HttpRequestPayload, Res any] struct {
+	newPayload func() Req
+	handler    HttpHandlerableWithPayloadAndResult[Req, Res]
+	status     HttpResponseSuccess
+}
+
+// Compile time check `httpHandlerChainResultImpl` implements `HttpHandlerChain`.
+var _ HttpHandlerChain[HttpRequestPayload] = (*httpHandlerChainResultImpl[HttpRequestPayload, any])(nil)
+
+// HttpHanlderChainWithPayloadAndResult returns `HttpHandlerChain` that wrap user's typed handler.
+// The request payload is bound & validated, then the returned result is written with `HttpStatusOK`
+// (see `WithStatus`) and the returned error is written through `ApiResponse.Error`.
+//
+//	router.Post("user", qore.HttpHanlderChainWithPayloadAndResult(m.HandlerUserCreate).WithStatus(qore.HttpStatusCreated))
+func HttpHanlderChainWithPayloadAndResult[Req HttpRequestPayload, Res any](
+	h HttpHandlerableWithPayloadAndResult[Req, Res],
+) *httpHandlerChainResultImpl[Req, Res] {
+	return &httpHandlerChainResultImpl[Req, Res]{
+		newPayload: httpRequestPayloadAllocator[Req](),
+		handler:    h,
+		status:     HttpStatusOK,
+	}
+}
+
+// WithStatus sets the HTTP(s) success status of the response.
+func (chain *httpHandlerChainResultImpl[Req, Res]) WithStatus(status HttpResponseSuccess) *httpHandlerChainResultImpl[Req, Res] {
+	chain.status = status
+	return chain
+}
+
+func (chain *httpHandlerChainResultImpl[Req, Res]) HandlerWrapper(c HttpContext) error {
+	if chain.handler == nil {
 		return c.Api().ServerError(
-			HttpStatusNotImplemented, errors.New("error Malfunction on wrap up the payload"),
+			HttpStatusNotImplemented, errors.New("error HTTP(s) handler must be defined"),
 		).Response()
-	} else {
-		// Do binding data.
-		if err := c.Bind(&chain.requestPayload); err != nil {
-			return c.Api().ClientError(
-				HttpStatusBadRequest, errors.New("error Invalid formating on request payload"),
-			).Response()
-		}
-		// Global validation on request payload.
-		if err := c.ValidateRequest(chain.requestPayload); err != nil {
-			defer err.Close()
-			e := fmt.Errorf("%s, %s", http.StatusText(int(HttpStatusBadRequest)), err.Error())
-			return c.Api().ClientError(HttpStatusBadRequest, e).Response()
-		}
-		// Custom validation from user on request payload.
-		if err := chain.requestPayload.Validate(); err != nil {
-			e := fmt.Errorf("%s, %s", http.StatusText(int(HttpStatusBadRequest)), err.Error())
-			return c.Api().ClientError(HttpStatusBadRequest, e).Response()
-		}
-	}
-	return chain.handlerWithPayload(c, chain.requestPayload)
+	}
+
+	payload, handled, err := bindPayload(c, chain.newPayload)
+	if handled {
+		return err
+	}
+	result, err := chain.handler(c, payload)
+
+	// Handler has written the response by itself.
+	if c.Response().Committed {
+		return err
+	}
+	if err != nil {
+		return c.Api().Error(err).Response()
+	}
+	if chain.status == HttpStatusNoContent {
+		return c.NoContent(int(chain.status))
+	}
+	return c.Api().Success(chain.status, result).Response()
+}
+
+// httpHandlerChainSchema is implemented by chain that knows its request payload & result type,
+// it is used by the OpenAPI document generation.
+type httpHandlerChainSchema interface {
+	payloadType() reflect.Type
+	resultType() reflect.Type
+	successStatus() HttpResponseSuccess
+}
+
+func (chain *httpHandlerChainImpl[T]) payloadType() reflect.Type {
+	if chain.handler != nil || chain.handlerWithPayload == nil {
+		return nil
+	}
+	return reflect.TypeFor[T]()
+}
+
+func (chain *httpHandlerChainImpl[T]) resultType() reflect.Type { return nil }
+
+func (chain *httpHandlerChainImpl[T]) successStatus() HttpResponseSuccess { return HttpStatusOK }
+
+func (chain *httpHandlerChainResultImpl[Req, Res]) payloadType() reflect.Type {
+	return reflect.TypeFor[Req]()
+}
+
+func (chain *httpHandlerChainResultImpl[Req, Res]) resultType() reflect.Type {
+	return reflect.TypeFor[Res]()
+}
+
+func (chain *httpHandlerChainResultImpl[Req, Res]) successStatus() HttpResponseSuccess {
+	return chain.status
 }
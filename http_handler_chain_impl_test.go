@@ -0,0 +1,185 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+const chainConcurrentTotal = 200
+
+type chainPayloadTest struct {
+	ID    int    `json:"id" validate:"required"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (p chainPayloadTest) Validate() error { return nil }
+
+type chainPointerPayloadTest struct {
+	ID    int      `json:"id" validate:"required"`
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Tags  []string `json:"tags"`
+}
+
+func (p *chainPointerPayloadTest) Validate() error { return nil }
+
+// serveChainConcurrently sends concurrent requests where every odd request only has `name`
+// and every even request only has `email`.
+func serveChainConcurrently(t *testing.T, chain HttpHandlerChain[HttpRequestPayload]) {
+	t.Helper()
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.POST("/", httpHandlerToEchoHandler(chain.HandlerWrapper, server, logger))
+
+	var wg sync.WaitGroup
+	for i := 1; i <= chainConcurrentTotal; i++ {
+		body := fmt.Sprintf(`{"id":%d,"name":"name-%d"}`, i, i)
+		if i%2 == 0 {
+			body = fmt.Sprintf(`{"id":%d,"email":"email-%d"}`, i, i)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			server.core.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("unexpected status %d: %s", rec.Code, rec.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHttpHandlerChainPayloadIsolationOk(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[int][2]string)
+	)
+	chain := HttpHanlderChainWithPayload(func(c HttpContext, p chainPayloadTest) error {
+		mu.Lock()
+		received[p.ID] = [2]string{p.Name, p.Email}
+		mu.Unlock()
+		return c.Api().Success(HttpStatusOK).Response()
+	})
+	serveChainConcurrently(t, chain)
+	assertChainPayloadIsolation(t, received)
+}
+
+func TestHttpHandlerChainPointerPayloadIsolationOk(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[int][2]string)
+		pointers = make(map[*chainPointerPayloadTest]struct{})
+	)
+	chain := HttpHanlderChainWithPayload(func(c HttpContext, p *chainPointerPayloadTest) error {
+		mu.Lock()
+		received[p.ID] = [2]string{p.Name, p.Email}
+		pointers[p] = struct{}{}
+		mu.Unlock()
+		return c.Api().Success(HttpStatusOK).Response()
+	})
+	serveChainConcurrently(t, chain)
+	assertChainPayloadIsolation(t, received)
+	if len(pointers) != len(received) {
+		t.Errorf("expected %d distinct payload, got %d", len(received), len(pointers))
+	}
+}
+
+func TestHttpHandlerChainPayloadFuncOk(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[int][2]string)
+	)
+	chain := HttpHanlderChainWithPayloadFunc(
+		func() *chainPointerPayloadTest { return &chainPointerPayloadTest{Tags: []string{}} },
+		func(c HttpContext, p *chainPointerPayloadTest) error {
+			if p.Tags == nil {
+				return errors.New("payload is not created by the constructor")
+			}
+			mu.Lock()
+			received[p.ID] = [2]string{p.Name, p.Email}
+			mu.Unlock()
+			return c.Api().Success(HttpStatusOK).Response()
+		},
+	)
+	serveChainConcurrently(t, chain)
+	assertChainPayloadIsolation(t, received)
+}
+
+func TestHttpHandlerChainInterfacePayloadErr(t *testing.T) {
+	chain := HttpHanlderChainWithPayload(func(c HttpContext, p HttpRequestPayload) error { return nil })
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.POST("/", httpHandlerToEchoHandler(chain.HandlerWrapper, server, logger))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	server.core.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected status %d, got %d", http.StatusNotImplemented, rec.Code)
+	}
+}
+
+func assertChainPayloadIsolation(t *testing.T, received map[int][2]string) {
+	t.Helper()
+	if len(received) != chainConcurrentTotal {
+		t.Fatalf("expected %d handled request, got %d", chainConcurrentTotal, len(received))
+	}
+	for id, fields := range received {
+		want := [2]string{fmt.Sprintf("name-%d", id), ""}
+		if id%2 == 0 {
+			want = [2]string{"", fmt.Sprintf("email-%d", id)}
+		}
+		if fields != want {
+			t.Errorf("request %d: expected %v, got %v", id, want, fields)
+		}
+	}
+}
+
+func TestHttpHandlerChainPayloadAndResultOk(t *testing.T) {
+	type result struct {
+		ID int `json:"id"`
+	}
+	chain := HttpHanlderChainWithPayloadAndResult(func(c HttpContext, p *chainPointerPayloadTest) (*result, error) {
+		if p.ID == 2 {
+			return nil, context.DeadlineExceeded
+		}
+		return &result{ID: p.ID}, nil
+	}).WithStatus(HttpStatusCreated)
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	server.core.POST("/", httpHandlerToEchoHandler(chain.HandlerWrapper, server, logger))
+
+	cases := []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"id":1}`, http.StatusCreated, `"data":{"id":1}`},
+		{`{"id":2}`, http.StatusGatewayTimeout, `"success":false`},
+		{`{}`, http.StatusBadRequest, `"success":false`},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		server.core.ServeHTTP(rec, req)
+		body := strings.Join(strings.Fields(rec.Body.String()), "")
+		if rec.Code != tc.status || !strings.Contains(body, tc.want) {
+			t.Errorf("%s: expected %d %s, got %d %s", tc.body, tc.status, tc.want, rec.Code, body)
+		}
+	}
+}
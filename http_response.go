@@ -42,6 +42,7 @@ const (
 	HttpStatusRequestTimeout    HttpResponseClientError = http.StatusRequestTimeout
 	HttpStatusConflict          HttpResponseClientError = http.StatusConflict
 	HttpStatusGone              HttpResponseClientError = http.StatusGone
+	HttpStatusTooManyRequests   HttpResponseClientError = http.StatusTooManyRequests
 )
 
 // HttpResponseServerError defines type of HTTP(s) status response server error.
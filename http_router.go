@@ -1,11 +1,19 @@
 package qore
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
+// httpRoute defines registered route of the HTTP server.
+type httpRoute struct {
+	method  string
+	path    string
+	handler HttpHandlerChain[HttpRequestPayload]
+}
+
 // HttpRouter defines router for the HTTP server.
 type HttpRouter struct {
 	server *httpServer
@@ -25,6 +33,21 @@ func (router *HttpRouter) clone() *HttpRouter {
 	return &c
 }
 
+// add registers a new route for a method & path, then records it for the route introspection.
+func (r *HttpRouter) add(method, path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
+	path = r.path(path)
+	h := httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger)
+	m := httpMiddlewareWrappers(r.server, r.logger, middlewares...)
+
+	var route *echo.Route
+	if r.group != nil {
+		route = r.group.Add(method, path, h, m...)
+	} else {
+		route = r.server.core.Add(method, path, h, m...)
+	}
+	r.server.routes = append(r.server.routes, httpRoute{method: route.Method, path: route.Path, handler: handler})
+}
+
 // Group creates a new router group with prefix.
 func (r *HttpRouter) Group(prefix string, fn func(group *HttpRouter), middlewares ...HttpMiddleware) {
 	if ValidationIsEmpty(prefix) {
@@ -37,162 +60,45 @@ func (r *HttpRouter) Group(prefix string, fn func(group *HttpRouter), middleware
 
 // Connect registers a new CONNECT route for a path.
 func (r *HttpRouter) Connect(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.CONNECT(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.CONNECT(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodConnect, path, handler, middlewares...)
 }
 
 // Delete registers a new DELETE route for a path.
 func (r *HttpRouter) Delete(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.DELETE(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.DELETE(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodDelete, path, handler, middlewares...)
 }
 
 // Get registers a new GET route for a path.
 func (r *HttpRouter) Get(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.GET(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.GET(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodGet, path, handler, middlewares...)
 }
 
 // Head registers a new HEAD route for a path.
 func (r *HttpRouter) Head(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.HEAD(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.HEAD(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodHead, path, handler, middlewares...)
 }
 
 // Options registers a new OPTIONS route for a path.
 func (r *HttpRouter) Options(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.OPTIONS(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.OPTIONS(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodOptions, path, handler, middlewares...)
 }
 
 // Patch registers a new PATCH route for a path.
 func (r *HttpRouter) Patch(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.PATCH(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.PATCH(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodPatch, path, handler, middlewares...)
 }
 
 // Post registers a new POST route for a path.
 func (r *HttpRouter) Post(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.POST(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.POST(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodPost, path, handler, middlewares...)
 }
 
 // Put registers a new PUT route for a path.
 func (r *HttpRouter) Put(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.PUT(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.PUT(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodPut, path, handler, middlewares...)
 }
 
 // Trace registers a new TRACE route for a path.
 func (r *HttpRouter) Trace(path string, handler HttpHandlerChain[HttpRequestPayload], middlewares ...HttpMiddleware) {
-	path = r.path(path)
-	if r.group != nil {
-		r.group.TRACE(
-			path,
-			httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-			httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-		)
-		return
-	}
-	r.server.core.TRACE(
-		path,
-		httpHandlerToEchoHandler(handler.HandlerWrapper, r.server, r.logger),
-		httpMiddlewareWrappers(r.server, r.logger, middlewares...)...,
-	)
+	r.add(http.MethodTrace, path, handler, middlewares...)
 }
@@ -0,0 +1,115 @@
+package qore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHttpServersTestApp(t *testing.T) *App {
+	t.Helper()
+	app := &App{Config: &Config{}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	if err := app.AddHttpServer(HTTP_SERVER_DEFAULT, HttpServerConfig{Port: 3100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.AddHttpServer(HTTP_SERVER_ADMIN, HttpServerConfig{Port: 3200}); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestAddHttpServerOk(t *testing.T) {
+	app := newHttpServersTestApp(t)
+	if app.httpServer != app.httpServers[HTTP_SERVER_DEFAULT] {
+		t.Error("expected default server is the default HTTP(s) server")
+	}
+	if len(app.servers) != 2 {
+		t.Errorf("expected 2 application servers, got %d", len(app.servers))
+	}
+
+	app.SetHttpRoutes(func(router *HttpRouter) {
+		router.Get("/public", HttpHanlderChain(func(c HttpContext) error {
+			return c.Api().Success(HttpStatusOK).Response()
+		}))
+	})
+	app.SetHttpRoutesOn(HTTP_SERVER_ADMIN, func(router *HttpRouter) {
+		router.Get("/private", HttpHanlderChain(func(c HttpContext) error {
+			return c.Api().Success(HttpStatusOK).Response()
+		}))
+	})
+	app.SetHttpRoutesOn("unknown", func(router *HttpRouter) {
+		t.Error("expected routes of unknown server is not set")
+	})
+
+	tests := []struct {
+		server string
+		path   string
+		status int
+	}{
+		{HTTP_SERVER_DEFAULT, "/public", http.StatusOK},
+		{HTTP_SERVER_DEFAULT, "/private", http.StatusNotFound},
+		{HTTP_SERVER_ADMIN, "/private", http.StatusOK},
+		{HTTP_SERVER_ADMIN, "/public", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		app.httpServers[test.server].core.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if rec.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d", test.server, test.path, test.status, rec.Code)
+		}
+	}
+}
+
+func TestAddHttpServerErr(t *testing.T) {
+	app := newHttpServersTestApp(t)
+	tests := []struct {
+		name   string
+		config HttpServerConfig
+	}{
+		{"", HttpServerConfig{Port: 3300}},
+		{"internal", HttpServerConfig{}},
+		{HTTP_SERVER_ADMIN, HttpServerConfig{Port: 3300}},
+	}
+	for _, test := range tests {
+		if err := app.AddHttpServer(test.name, test.config); err == nil {
+			t.Errorf("%q: expected error", test.name)
+		}
+	}
+}
+
+type httpServersValidationTest struct {
+	Count int `json:"count" validate:"even"`
+}
+
+func TestAddHttpServerValidationOk(t *testing.T) {
+	app := newHttpServersTestApp(t)
+	if err := app.RegisterValidation("even", func(fl HttpValidationFieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RegisterValidationMessage(HTTP_VALIDATOR_LOCALE_EN, "even", "{0} must be even"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server that is added and the validator that is set after the registration.
+	if err := app.AddHttpServer("internal", HttpServerConfig{Port: 3300}); err != nil {
+		t.Fatal(err)
+	}
+	app.SetHttpValidatorOn(HTTP_SERVER_ADMIN, httpValidatorDefault())
+
+	for name, server := range app.httpServers {
+		verr := server.validator.Validate(httpServersValidationTest{Count: 1})
+		if verr == nil || len(verr.Fields) != 1 {
+			t.Errorf("%s: expected validation error, got %v", name, verr)
+			continue
+		}
+		if verr.Fields[0].Tag != "even" || verr.Fields[0].Message != "count must be even" {
+			t.Errorf("%s: unexpected field error %+v", name, verr.Fields[0])
+		}
+		if verr := server.validator.Validate(httpServersValidationTest{Count: 2}); verr != nil {
+			t.Errorf("%s: unexpected validation error %v", name, verr)
+		}
+	}
+}
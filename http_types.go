@@ -15,6 +15,24 @@ type HttpValidator interface {
 	Validate(a any) *HttpValidatorErr
 }
 
+// HttpValidatorLocalized is optional interface of `HttpValidator` to validate the request with
+// localized error message, the locale is picked from the `Accept-Language` request header.
+type HttpValidatorLocalized interface {
+	// ValidateLocalized validates the given interface with message of the accepted language.
+	ValidateLocalized(a any, acceptLanguage string) *HttpValidatorErr
+	// RegisterMessage registers custom message of validation tag for the locale, the locale is added
+	// when it is not registered yet. `{0}` is replaced by the field name and `{1}` is replaced by the tag parameter.
+	RegisterMessage(locale, tag, message string) error
+}
+
+// HttpValidatorRegistrable is optional interface of `HttpValidator` to register custom validation rules.
+type HttpValidatorRegistrable interface {
+	// RegisterValidation registers custom validation rule by the tag.
+	RegisterValidation(tag string, fn HttpValidationFunc) error
+	// RegisterStructValidation registers struct level validation for the given struct types.
+	RegisterStructValidation(fn HttpStructValidationFunc, types ...any)
+}
+
 // ApiResponseInterface defines the `HttpResponder` interface to creating the instance.
 type ApiResponseInterface interface {
 	New(c HttpContext) ApiResponse
@@ -33,14 +51,23 @@ type ApiResponse interface {
 	ServerError(status HttpResponseServerError, err error) ApiResponse
 	// WithMessage returns `ApiResponse` with response message.
 	WithMessage(msg string) ApiResponse
-	// WithMessage returns `ApiResponse` with response code.
+	// WithCode returns `ApiResponse` with response code.
 	WithCode(code any) ApiResponse
-	// WithMessage returns `ApiResponse` with additional data in the response.
+	// WithAdditional returns `ApiResponse` with additional data in the response.
 	WithAdditional(data any) ApiResponse
 	// Response write the `ApiResponse`. Returning error or nil.
 	Response() error
 }
 
+// ApiResponsePaginated is optional interface of `ApiResponse` to write pagination metadata & HATEOAS links,
+// use `ApiResponseWithPagination` and `ApiResponseWithLinks` to set them into any `ApiResponse`.
+type ApiResponsePaginated interface {
+	// WithPagination returns `ApiResponse` with pagination metadata in the response.
+	WithPagination(pagination ApiResponsePagination) ApiResponse
+	// WithLinks returns `ApiResponse` with HATEOAS links of the resource.
+	WithLinks(links ...ApiResponseLink) ApiResponse
+}
+
 // HttpContext represents the context of the current HTTP(s) request.
 // It holds request and response objects, path, path parameters, data and registered handler.
 // It is abstraction copy from echo.Context.
@@ -182,6 +209,10 @@ type HttpHandlerable HttpHandler
 // HttpHandlerableWithPayload same like `HttpHandlerable` but need & use request payload.
 type HttpHandlerableWithPayload[T HttpRequestPayload] func(c HttpContext, p T) error
 
+// HttpHandlerableWithPayloadAndResult same like `HttpHandlerableWithPayload` but returns the response data,
+// the chain writes the returned data using `ApiResponse.Success` or the returned error using `ApiResponse.Error`.
+type HttpHandlerableWithPayloadAndResult[Req HttpRequestPayload, Res any] func(c HttpContext, req Req) (Res, error)
+
 // HttpHandlerChain defines HTTP(s) handler wrapper.
 type HttpHandlerChain[T HttpRequestPayload] interface {
 	// HandlerWrapper is wrapper function for HTTP(s) handler.
@@ -1,16 +1,40 @@
 package qore
 
+import "strings"
+
+// HttpValidatorFieldErr defines validation error of a single field.
+type HttpValidatorFieldErr struct {
+	// Field is JSON path of the field, e.g. `address.city` or `items[0].qty`.
+	Field string `json:"field" xml:"field"`
+	// Tag is the failed validation tag, e.g. `required` or `min`.
+	Tag string `json:"tag" xml:"tag"`
+	// Param is the parameter of the failed validation tag, e.g. `3` of `min=3`.
+	Param string `json:"param,omitempty" xml:"param,omitempty"`
+	// Message is human readable error message.
+	Message string `json:"message" xml:"message"`
+}
+
 // HttpValidatorErr defines http validator error wrap.
 type HttpValidatorErr struct {
 	ErrMandatory error
 	ErrFormat    error
 
+	// Fields holds every invalid field of the request.
+	Fields []HttpValidatorFieldErr
+
 	// Internal used for auto put object to the pool.
 	recycle func(*HttpValidatorErr)
 }
 
 // Error implements error interface.
 func (e *HttpValidatorErr) Error() string {
+	if len(e.Fields) > 0 {
+		messages := make([]string, len(e.Fields))
+		for i, field := range e.Fields {
+			messages[i] = field.Message
+		}
+		return strings.Join(messages, ", ")
+	}
 	if e.ErrMandatory != nil {
 		return e.ErrMandatory.Error()
 	}
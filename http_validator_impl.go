@@ -8,16 +8,29 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
 )
 
 type httpValidatorImpl struct {
 	validate *validator.Validate
+	uni      *ut.UniversalTranslator
+	locale   string
 }
 
 // Compile time check `httpValidatorImpl implements `HttpValidator`.
 var _ HttpValidator = (*httpValidatorImpl)(nil)
 
+// Compile time check `httpValidatorImpl implements `HttpValidatorLocalized`.
+var _ HttpValidatorLocalized = (*httpValidatorImpl)(nil)
+
+// Compile time check `httpValidatorImpl implements `HttpValidatorRegistrable`.
+var _ HttpValidatorRegistrable = (*httpValidatorImpl)(nil)
+
 var httpValidatorErrPool = sync.Pool{
 	New: func() any {
 		return new(HttpValidatorErr)
@@ -31,6 +44,7 @@ func putHttpValidatorErr(verr *HttpValidatorErr) {
 	}
 	verr.ErrMandatory = nil
 	verr.ErrFormat = nil
+	verr.Fields = verr.Fields[:0]
 	verr.recycle = nil
 	httpValidatorErrPool.Put(verr)
 }
@@ -61,23 +75,169 @@ func (v *httpValidatorImpl) httpValidateIpCidr(field validator.FieldLevel) bool
 	}
 }
 
-// httpValidatorDefault creates new default HTTP(S) validator implementation.
+// httpValidatorDefault creates new default HTTP(S) validator implementation
+// with built-in `en` and `id` locales.
 func httpValidatorDefault() *httpValidatorImpl {
 	validator := &httpValidatorImpl{
 		validate: validator.New(),
+		uni:      ut.New(en.New()),
+		locale:   HTTP_VALIDATOR_LOCALE_EN,
 	}
 	validator.validate.RegisterValidation("ip_or_cidr", validator.httpValidateIpCidr)
 	validator.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
-		tag := field.Tag.Get("json")
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
 		if tag == "-" {
 			return ""
 		}
+		if tag == "" {
+			return field.Name
+		}
 		return tag
 	})
+
+	// Built-in rules.
+	for tag, fn := range httpValidationRules {
+		validator.validate.RegisterValidation(tag, fn)
+	}
+
+	// Built-in locales, the `en` locale keeps the built-in message (see `httpValidatorMessage`)
+	// of the tag without custom message.
+	validator.addTranslator(id.New(), idTranslations.RegisterDefaultTranslations)
+	for locale, messages := range httpValidationMessages {
+		for tag, message := range messages {
+			validator.RegisterMessage(locale, tag, message)
+		}
+	}
 	return validator
 }
 
+// RegisterValidation registers custom validation rule by the tag.
+func (v *httpValidatorImpl) RegisterValidation(tag string, fn HttpValidationFunc) error {
+	if ValidationIsEmpty(tag) || fn == nil {
+		return errors.New("validation tag and function are required")
+	}
+	return v.validate.RegisterValidation(tag, fn)
+}
+
+// RegisterStructValidation registers struct level validation for the given struct types.
+func (v *httpValidatorImpl) RegisterStructValidation(fn HttpStructValidationFunc, types ...any) {
+	v.validate.RegisterStructValidation(fn, types...)
+}
+
+// addTranslator adds translator of the locale with its messages register function.
+func (v *httpValidatorImpl) addTranslator(
+	translator locales.Translator, register func(v *validator.Validate, trans ut.Translator) error,
+) error {
+	if err := v.uni.AddTranslator(translator, true); err != nil {
+		return fmt.Errorf("failed to add validator translator %s: %w", translator.Locale(), err)
+	}
+	if register == nil {
+		return nil
+	}
+	trans, _ := v.uni.GetTranslator(translator.Locale())
+	if err := register(v.validate, trans); err != nil {
+		return fmt.Errorf("failed to register validator messages %s: %w", translator.Locale(), err)
+	}
+	return nil
+}
+
+// httpValidatorLocale is translator of the locale that is added by the custom message,
+// it uses the English rules since the message does not have plural form.
+type httpValidatorLocale struct {
+	locales.Translator
+	locale string
+}
+
+func (l httpValidatorLocale) Locale() string { return l.locale }
+
+// RegisterMessage registers custom message of validation tag for the locale, the locale is added
+// when it is not registered yet.
+func (v *httpValidatorImpl) RegisterMessage(locale, tag, message string) error {
+	tags := httpAcceptLanguages(locale)
+	if len(tags) == 0 || ValidationIsEmpty(tag) {
+		return errors.New("validator message locale and tag are required")
+	}
+	locale = tags[0]
+	trans, found := v.uni.GetTranslator(locale)
+	if !found {
+		if err := v.addTranslator(httpValidatorLocale{Translator: en.New(), locale: locale}, nil); err != nil {
+			return err
+		}
+		trans, _ = v.uni.GetTranslator(locale)
+	}
+	return v.validate.RegisterTranslation(tag, trans,
+		func(t ut.Translator) error { return t.Add(tag, message, true) },
+		func(t ut.Translator, fe validator.FieldError) string {
+			msg, err := t.T(tag, fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+			return msg
+		},
+	)
+}
+
+// translator returns translator of the first supported language of `Accept-Language` header,
+// otherwise returns translator of the default locale.
+func (v *httpValidatorImpl) translator(acceptLanguage string) ut.Translator {
+	for _, lang := range httpAcceptLanguages(acceptLanguage) {
+		if trans, found := v.uni.GetTranslator(lang); found {
+			return trans
+		}
+	}
+	trans, _ := v.uni.GetTranslator(v.locale)
+	return trans
+}
+
+// httpAcceptLanguages parses `Accept-Language` header into locales ordered by the quality value,
+// region locale is followed by its base language, e.g. `id-ID` is `id_ID` and `id`.
+func httpAcceptLanguages(header string) []string {
+	var locales []string
+	for _, tag := range httpAcceptValues(header) {
+		if tag == "*" {
+			continue
+		}
+		base, region, ok := strings.Cut(strings.ReplaceAll(tag, "-", "_"), "_")
+		if ok {
+			locales = append(locales, base+"_"+strings.ToUpper(region))
+		}
+		locales = append(locales, base)
+	}
+	return locales
+}
+
+// httpValidatorFieldPath returns JSON path of the field error without the root struct name.
+func httpValidatorFieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+// httpValidatorMessage returns human readable message of the field error.
+func httpValidatorMessage(field string, fe validator.FieldError) string {
+	switch tag := fe.Tag(); {
+	case strings.Contains(tag, "required"):
+		return fmt.Sprintf("field [%s] cannot be empty", field)
+	case tag == "min" || tag == "max" || tag == "len":
+		return fmt.Sprintf("field [%s] is invalid, %s length %s", field, tag, fe.Param())
+	case tag == "gt" || tag == "gte" || tag == "lt" || tag == "lte":
+		return fmt.Sprintf("field [%s] is invalid, must be %s %s", field, tag, fe.Param())
+	case tag == "oneof":
+		return fmt.Sprintf("field [%s] is invalid, must be one of [%s]", field, fe.Param())
+	default:
+		return fmt.Sprintf("field [%s] is invalid", field)
+	}
+}
+
+// Validate validates the given interface with message of the default locale.
 func (v *httpValidatorImpl) Validate(source any) *HttpValidatorErr {
+	return v.ValidateLocalized(source, "")
+}
+
+// ValidateLocalized validates the given interface with message of the accepted language.
+func (v *httpValidatorImpl) ValidateLocalized(source any, acceptLanguage string) *HttpValidatorErr {
 	err := v.validate.Struct(source)
 	fieldErrs, ok := err.(validator.ValidationErrors)
 	if ok {
@@ -87,17 +247,30 @@ func (v *httpValidatorImpl) Validate(source any) *HttpValidatorErr {
 
 		// If field errors is any.
 		if len(fieldErrs) > 0 {
-			fe := fieldErrs[0]
-			field := StringRemoveNonAlphabet(fe.Field())
-
-			// Parse error from struct validator.
-			switch {
-			default:
-				verr.ErrFormat = fmt.Errorf("field [%s] is invalid", field)
-			case strings.Contains(fe.Tag(), "required"):
-				verr.ErrMandatory = fmt.Errorf("field [%s] cannot be empty", field)
-			case fe.Tag() == "min" || fe.Tag() == "max":
-				verr.ErrFormat = fmt.Errorf("field [%s] is invalid, %s length %s", field, fe.Tag(), fe.Param())
+			trans := v.translator(acceptLanguage)
+			for _, fe := range fieldErrs {
+				field := httpValidatorFieldPath(fe)
+
+				// Tag without translation message is fallback to the built-in message.
+				message := fe.Translate(trans)
+				if message == fe.Error() {
+					message = httpValidatorMessage(field, fe)
+				}
+				verr.Fields = append(verr.Fields, HttpValidatorFieldErr{
+					Field:   field,
+					Tag:     fe.Tag(),
+					Param:   fe.Param(),
+					Message: message,
+				})
+
+				// Keep the first mandatory & format error.
+				if strings.Contains(fe.Tag(), "required") {
+					if verr.ErrMandatory == nil {
+						verr.ErrMandatory = errors.New(message)
+					}
+				} else if verr.ErrFormat == nil {
+					verr.ErrFormat = errors.New(message)
+				}
 			}
 			return verr
 		}
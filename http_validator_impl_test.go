@@ -0,0 +1,142 @@
+package qore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type validatorAddressTest struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validatorPayloadTest struct {
+	Name    string                 `json:"name,omitempty" validate:"required"`
+	Age     int                    `json:"age" validate:"gte=17"`
+	Address validatorAddressTest   `json:"address"`
+	Items   []validatorAddressTest `json:"items" validate:"dive"`
+}
+
+func (p *validatorPayloadTest) Validate() error { return nil }
+
+func TestHttpValidatorMultiFieldOk(t *testing.T) {
+	verr := httpValidatorDefault().Validate(&validatorPayloadTest{Age: 10, Items: []validatorAddressTest{{}}})
+	if verr == nil {
+		t.Fatal("expected validation error")
+	}
+	defer verr.Close()
+
+	expected := []string{"name", "age", "address.city", "items[0].city"}
+	if len(verr.Fields) != len(expected) {
+		t.Fatalf("unexpected fields %+v", verr.Fields)
+	}
+	for i, field := range verr.Fields {
+		if field.Field != expected[i] {
+			t.Errorf("expected field %s, got %s", expected[i], field.Field)
+		}
+	}
+	if verr.Fields[1].Tag != "gte" || verr.Fields[1].Param != "17" {
+		t.Errorf("unexpected tag %+v", verr.Fields[1])
+	}
+	if verr.ErrMandatory == nil || verr.ErrFormat == nil {
+		t.Errorf("expected mandatory & format error, got %v & %v", verr.ErrMandatory, verr.ErrFormat)
+	}
+}
+
+func TestHttpValidatorResponseOk(t *testing.T) {
+	server := newHttpServer(HTTP_SERVER_DEFAULT)
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	chain := HttpHanlderChainWithPayload(func(c HttpContext, p *validatorPayloadTest) error {
+		return c.Api().Success(HttpStatusOK).Response()
+	})
+	server.core.POST("/", httpHandlerToEchoHandler(chain.HandlerWrapper, server, logger))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":20}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	server.core.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+
+	var response struct {
+		Error ApiResponseErrorDetail `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Error.Fields) != 2 || response.Error.Fields[0].Field != "name" || response.Error.Fields[1].Field != "address.city" {
+		t.Errorf("unexpected error payload %s", rec.Body.String())
+	}
+}
+
+func TestHttpValidatorLocalizedOk(t *testing.T) {
+	v := httpValidatorDefault()
+	if err := v.RegisterMessage(HTTP_VALIDATOR_LOCALE_ID, "gte", "{0} minimal {1}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.RegisterMessage("pt-BR", "required", "{0} é obrigatório"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.RegisterMessage("", "required", "{0}"); err == nil {
+		t.Error("expected locale is required error")
+	}
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{acceptLanguage: "", expected: "field [name] cannot be empty"},
+		{acceptLanguage: "fr-FR, id-ID;q=0.9, en;q=0.8", expected: "name wajib diisi"},
+		{acceptLanguage: "en;q=0.5, id", expected: "name wajib diisi"},
+		{acceptLanguage: "fr", expected: "field [name] cannot be empty"},
+		{acceptLanguage: "pt-BR", expected: "name é obrigatório"},
+	}
+	for _, test := range tests {
+		verr := v.ValidateLocalized(&validatorPayloadTest{Age: 10, Address: validatorAddressTest{City: "x"}}, test.acceptLanguage)
+		if verr == nil {
+			t.Fatal("expected validation error")
+		}
+		if verr.Fields[0].Message != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.acceptLanguage, test.expected, verr.Fields[0].Message)
+		}
+		if test.acceptLanguage == "en;q=0.5, id" && verr.Fields[1].Message != "age minimal 17" {
+			t.Errorf("unexpected custom message %q", verr.Fields[1].Message)
+		}
+		verr.Close()
+	}
+}
+
+func TestHttpValidatorLocalizedFieldOk(t *testing.T) {
+	v := httpValidatorDefault()
+	if err := v.RegisterMessage(HTTP_VALIDATOR_LOCALE_EN, "gte", "{0} must be at least {1}"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		acceptLanguage string
+		expected       []string
+	}{
+		// The tag without custom message keeps the built-in message of the default locale.
+		{"", []string{"field [name] cannot be empty", "age must be at least 17", "field [address.city] cannot be empty"}},
+		// The translation message has the field name, the JSON path is in the field error.
+		{"id", []string{"name wajib diisi", "age harus 17 atau lebih besar", "city wajib diisi"}},
+	}
+	for _, test := range tests {
+		verr := v.ValidateLocalized(&validatorPayloadTest{Age: 10}, test.acceptLanguage)
+		if verr == nil {
+			t.Fatal("expected validation error")
+		}
+		if len(verr.Fields) != len(test.expected) || verr.Fields[2].Field != "address.city" {
+			t.Fatalf("%q: unexpected fields %+v", test.acceptLanguage, verr.Fields)
+		}
+		for i, field := range verr.Fields {
+			if field.Message != test.expected[i] {
+				t.Errorf("%q: expected %q, got %q", test.acceptLanguage, test.expected[i], field.Message)
+			}
+		}
+		verr.Close()
+	}
+}
@@ -0,0 +1,110 @@
+package qore
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// HttpValidationFunc defines validation rule function of a field.
+type HttpValidationFunc = validator.Func
+
+// HttpValidationFieldLevel defines field information of the validation rule function.
+type HttpValidationFieldLevel = validator.FieldLevel
+
+// HttpStructValidationFunc defines struct level validation function.
+type HttpStructValidationFunc = validator.StructLevelFunc
+
+// HttpValidationStructLevel defines struct information of the struct level validation function.
+type HttpValidationStructLevel = validator.StructLevel
+
+var (
+	httpValidationIdPhoneRegex = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,11}$`)
+	httpValidationNikRegex     = regexp.MustCompile(`^[0-9]{16}$`)
+	httpValidationSlugRegex    = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	httpValidationUuid7Regex   = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+)
+
+// httpValidationRules defines built-in validation rules of the default validator.
+var httpValidationRules = map[string]HttpValidationFunc{
+	HTTP_VALIDATION_ID_PHONE:        httpValidateString(httpValidationIdPhoneRegex.MatchString),
+	HTTP_VALIDATION_NIK:             httpValidateString(httpValidateNik),
+	HTTP_VALIDATION_SLUG:            httpValidateString(httpValidationSlugRegex.MatchString),
+	HTTP_VALIDATION_STRONG_PASSWORD: httpValidateString(httpValidateStrongPassword),
+	HTTP_VALIDATION_UUID7:           httpValidateString(httpValidationUuid7Regex.MatchString),
+}
+
+// httpValidationMessages defines messages of the built-in validation rules per locale.
+var httpValidationMessages = map[string]map[string]string{
+	HTTP_VALIDATOR_LOCALE_EN: {
+		"ip_or_cidr":                    "{0} must be a valid IP address or CIDR",
+		HTTP_VALIDATION_ID_PHONE:        "{0} must be a valid Indonesian phone number",
+		HTTP_VALIDATION_NIK:             "{0} must be a valid NIK",
+		HTTP_VALIDATION_SLUG:            "{0} must be a valid slug",
+		HTTP_VALIDATION_STRONG_PASSWORD: "{0} must be at least 8 characters with upper case, lower case, number and symbol",
+		HTTP_VALIDATION_UUID7:           "{0} must be a valid UUIDv7",
+	},
+	HTTP_VALIDATOR_LOCALE_ID: {
+		"ip_or_cidr":                    "{0} harus berupa alamat IP atau CIDR yang valid",
+		HTTP_VALIDATION_ID_PHONE:        "{0} harus berupa nomor telepon Indonesia yang valid",
+		HTTP_VALIDATION_NIK:             "{0} harus berupa NIK yang valid",
+		HTTP_VALIDATION_SLUG:            "{0} harus berupa slug yang valid",
+		HTTP_VALIDATION_STRONG_PASSWORD: "{0} minimal 8 karakter dengan huruf besar, huruf kecil, angka dan simbol",
+		HTTP_VALIDATION_UUID7:           "{0} harus berupa UUIDv7 yang valid",
+	},
+}
+
+// httpValidateString wraps string checker into validation rule, empty string is valid
+// so it can be combined with `omitempty` or `required`.
+func httpValidateString(checker func(string) bool) HttpValidationFunc {
+	return func(field HttpValidationFieldLevel) bool {
+		if field.Field().Kind() != reflect.String {
+			return false
+		}
+		val := field.Field().String()
+		return val == "" || checker(val)
+	}
+}
+
+// httpValidateNik returns true if given value is valid Indonesian NIK (Nomor Induk Kependudukan),
+// 16 digits of province, regency, district, birth date (day + 40 for female) and serial number.
+func httpValidateNik(val string) bool {
+	if !httpValidationNikRegex.MatchString(val) {
+		return false
+	}
+	part := func(from, to int) int {
+		n, _ := strconv.Atoi(val[from:to])
+		return n
+	}
+	province, regency, district := part(0, 2), part(2, 4), part(4, 6)
+	day, month, serial := part(6, 8), part(8, 10), part(12, 16)
+	if day > 40 {
+		day -= 40
+	}
+	return province >= 11 && province <= 94 && regency > 0 && district > 0 &&
+		day >= 1 && day <= 31 && month >= 1 && month <= 12 && serial > 0
+}
+
+// httpValidateStrongPassword returns true if given value has at least 8 characters
+// with upper case, lower case, number and symbol.
+func httpValidateStrongPassword(val string) bool {
+	var upper, lower, number, symbol bool
+	count := 0
+	for _, r := range val {
+		count++
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			number = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	return count >= 8 && upper && lower && number && symbol
+}
@@ -0,0 +1,75 @@
+package qore
+
+import (
+	"testing"
+)
+
+type validatorRulesTest struct {
+	Phone    string `json:"phone" validate:"omitempty,id_phone"`
+	Nik      string `json:"nik" validate:"omitempty,nik"`
+	Slug     string `json:"slug" validate:"omitempty,slug"`
+	Password string `json:"password" validate:"omitempty,strong_password"`
+	Ulid     string `json:"ulid" validate:"omitempty,ulid"`
+	Uuid7    string `json:"uuid7" validate:"omitempty,uuid7"`
+	Even     int    `json:"even" validate:"even"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+func TestHttpValidatorRulesOk(t *testing.T) {
+	v := httpValidatorDefault()
+	if err := v.RegisterValidation("even", func(fl HttpValidationFieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}); err != nil {
+		t.Fatal(err)
+	}
+	v.RegisterStructValidation(func(sl HttpValidationStructLevel) {
+		req := sl.Current().Interface().(validatorRulesTest)
+		if req.End < req.Start {
+			sl.ReportError(req.End, "end", "End", "gtefield", "start")
+		}
+	}, validatorRulesTest{})
+
+	valid := validatorRulesTest{
+		Phone:    "+6281234567890",
+		Nik:      "3174015508900001",
+		Slug:     "hello-world-2",
+		Password: "S3cret!pass",
+		Ulid:     "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		Uuid7:    "01890a5d-ac96-774b-bcce-b302099a8057",
+		Start:    1,
+		End:      2,
+	}
+	if verr := v.Validate(valid); verr != nil {
+		t.Fatalf("unexpected error %+v", verr.Fields)
+	}
+
+	invalid := validatorRulesTest{
+		Phone:    "021555123",
+		Nik:      "3174017308900001",
+		Slug:     "Hello World",
+		Password: "password",
+		Ulid:     "not-ulid",
+		Uuid7:    "9b2f6a34-8d4b-4f0e-9a2c-6a2b3f1d9e10",
+		Even:     1,
+		Start:    2,
+		End:      1,
+	}
+	verr := v.ValidateLocalized(invalid, HTTP_VALIDATOR_LOCALE_ID)
+	if verr == nil {
+		t.Fatal("expected validation error")
+	}
+	defer verr.Close()
+	expected := []string{"phone", "nik", "slug", "password", "ulid", "uuid7", "even", "end"}
+	if len(verr.Fields) != len(expected) {
+		t.Fatalf("unexpected fields %+v", verr.Fields)
+	}
+	for i, field := range verr.Fields {
+		if field.Field != expected[i] {
+			t.Errorf("expected field %s, got %s", expected[i], field.Field)
+		}
+	}
+	if verr.Fields[1].Message != "nik harus berupa NIK yang valid" {
+		t.Errorf("unexpected message %q", verr.Fields[1].Message)
+	}
+}
@@ -0,0 +1,164 @@
+package httpmw
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qoinlyid/qore"
+)
+
+// MetricsConfig defines the config for Metrics middleware.
+type MetricsConfig struct {
+	// Skipper defines a function to skip middleware, see `SkipPaths`.
+	// Optional. Default value DefaultSkipper.
+	Skipper Skipper
+	// Registerer is registry of the HTTP(s) metrics collectors, e.g. `app.MetricsRegistry()`.
+	// Optional. Default value `prometheus.DefaultRegisterer`.
+	Registerer prometheus.Registerer
+	// Namespace is prefix of the metric name.
+	// Optional. Default value `qore`.
+	Namespace string
+	// Buckets is the request duration histogram buckets in seconds.
+	// Optional. Default value `prometheus.DefBuckets`.
+	Buckets []float64
+	// SizeBuckets is the response size histogram buckets in bytes.
+	// Optional. Default value from 100B up to 10MB.
+	SizeBuckets []float64
+}
+
+// DefaultMetricsConfig is Metrics default config.
+var DefaultMetricsConfig = &MetricsConfig{}
+
+// metricsDefaultCollectors returns the collectors of `Metrics` that are registered once,
+// the middleware is applied per request.
+var metricsDefaultCollectors = sync.OnceValue(func() *metricsCollectors {
+	return newMetricsCollectors(DefaultMetricsConfig)
+})
+
+// metricsUnmatchedRoute is route label of the request that does not match any registered route,
+// so the raw URL path does not explode the metric cardinality.
+const metricsUnmatchedRoute = "unmatched"
+
+type metricsCollectors struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// metricsRegister registers the collector, the already registered collector is reused
+// so the middleware can be used by multiple HTTP(s) servers.
+func metricsRegister[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if err := registerer.Register(collector); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
+
+func newMetricsCollectors(config *MetricsConfig) *metricsCollectors {
+	registerer := config.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = "qore"
+	}
+	buckets := config.Buckets
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+	sizeBuckets := config.SizeBuckets
+	if len(sizeBuckets) == 0 {
+		sizeBuckets = prometheus.ExponentialBuckets(100, 10, 6)
+	}
+
+	labels := []string{"method", "route", "status"}
+	return &metricsCollectors{
+		requests: metricsRegister(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "http", Name: "requests_total",
+			Help: "Total number of the HTTP(s) requests.",
+		}, labels)),
+		duration: metricsRegister(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds",
+			Help: "Duration of the HTTP(s) requests in seconds.", Buckets: buckets,
+		}, labels)),
+		size: metricsRegister(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "http", Name: "response_size_bytes",
+			Help: "Size of the HTTP(s) responses in bytes.", Buckets: sizeBuckets,
+		}, labels)),
+		inFlight: metricsRegister(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "http", Name: "requests_in_flight",
+			Help: "Number of the HTTP(s) requests being served.",
+		})),
+	}
+}
+
+func metricsHandler(next qore.HttpHandler, config *MetricsConfig, m *metricsCollectors) qore.HttpHandler {
+	skipper := config.Skipper
+	if skipper == nil {
+		skipper = DefaultSkipper
+	}
+
+	return func(c qore.HttpContext) (err error) {
+		if skipper(c) {
+			return next(c)
+		}
+
+		m.inFlight.Inc()
+		start := time.Now()
+
+		// Record the request after the handler chain, panic is recorded as 500 then re-panicked.
+		panicked := true
+		defer func() {
+			m.inFlight.Dec()
+			route := c.Path()
+			if route == "" {
+				route = metricsUnmatchedRoute
+			}
+			status := requestLogStatus(c, err, panicked)
+			labels := []string{c.Request().Method, route, strconv.Itoa(status)}
+			m.requests.WithLabelValues(labels...).Inc()
+			m.duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+			m.size.WithLabelValues(labels...).Observe(float64(c.Response().Size))
+		}()
+
+		err = next(c)
+		panicked = false
+		return err
+	}
+}
+
+// MetricsWithConfig returns a Prometheus HTTP(s) RED metrics middleware with config, the request
+// is labeled by the registered route path (e.g. `/user/:id`) instead of the raw URI.
+// See: `Metrics()`.
+func MetricsWithConfig(config *MetricsConfig) qore.HttpMiddleware {
+	if config == nil {
+		config = DefaultMetricsConfig
+	}
+	m := newMetricsCollectors(config)
+	return func(next qore.HttpHandler) qore.HttpHandler {
+		return metricsHandler(next, config, m)
+	}
+}
+
+// Metrics returns a Prometheus HTTP(s) RED metrics middleware, the metrics is registered into
+// `prometheus.DefaultRegisterer` and served by the metrics endpoint (`HTTP_METRICS_ENABLED`).
+//
+//	app.SetHttpMiddleware(httpmw.Metrics, httpmw.RequestLog)
+//
+// Use `MetricsWithConfig` to register the metrics into the application registry instead.
+//
+//	app.SetHttpMiddleware(httpmw.MetricsWithConfig(&httpmw.MetricsConfig{Registerer: app.MetricsRegistry()}))
+func Metrics(next qore.HttpHandler) qore.HttpHandler {
+	return metricsHandler(next, DefaultMetricsConfig, metricsDefaultCollectors())
+}
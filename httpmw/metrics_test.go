@@ -0,0 +1,48 @@
+package httpmw
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/qoinlyid/qore"
+)
+
+func TestMetricsOk(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	mw := MetricsWithConfig(&MetricsConfig{Registerer: registry})
+
+	handlers := []struct {
+		method, target, route string
+		handler               qore.HttpHandlerable
+	}{
+		{http.MethodGet, "/users/1", "/users/:id", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }},
+		{http.MethodGet, "/users/2", "/users/:id", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }},
+		{http.MethodPost, "/users", "/users", func(c qore.HttpContext) error { return errors.New("failed") }},
+		{http.MethodGet, "/unknown", "/users", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }},
+	}
+	for _, h := range handlers {
+		serveTest(newTestApp(h.route, h.handler, mw), httptest.NewRequest(h.method, h.target, nil))
+	}
+
+	// The already registered collectors are reused.
+	_ = MetricsWithConfig(&MetricsConfig{Registerer: registry})
+
+	expected := `
+# HELP qore_http_requests_total Total number of the HTTP(s) requests.
+# TYPE qore_http_requests_total counter
+qore_http_requests_total{method="GET",route="/users/:id",status="200"} 2
+qore_http_requests_total{method="GET",route="unmatched",status="404"} 1
+qore_http_requests_total{method="POST",route="/users",status="500"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "qore_http_requests_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(registry, "qore_http_request_duration_seconds"); n != 3 {
+		t.Errorf("expected 3 duration series, got %d", n)
+	}
+}
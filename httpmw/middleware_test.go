@@ -0,0 +1,27 @@
+package httpmw
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/qoinlyid/qore"
+)
+
+// newTestApp returns the app that serves GET & POST route of the path by the handler, the middlewares
+// are applied on the default HTTP(s) server so the middleware is tested with the real `qore.HttpContext`.
+func newTestApp(path string, handler qore.HttpHandlerable, middlewares ...qore.HttpMiddleware) *qore.App {
+	app := qore.New()
+	app.SetHttpMiddleware(middlewares...)
+	app.SetHttpRoutes(func(router *qore.HttpRouter) {
+		router.Get(path, qore.HttpHanlderChain(handler))
+		router.Post(path, qore.HttpHanlderChain(handler))
+	})
+	return app
+}
+
+// serveTest serves the request by the app and returns the recorded response.
+func serveTest(app *qore.App, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,321 @@
+package httpmw
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/qoinlyid/qore"
+)
+
+// RateLimitKeyFunc defines a function to extract the rate limit key of the request.
+type RateLimitKeyFunc func(c qore.HttpContext) (string, error)
+
+// RateLimitConfig defines the config for RateLimit middleware.
+type RateLimitConfig struct {
+	// Skipper defines a function to skip middleware, see `SkipPaths`.
+	// Optional. Default value DefaultSkipper.
+	Skipper Skipper
+	// Algorithm of the rate limiter.
+	// Optional. Default value RATE_LIMIT_TOKEN_BUCKET.
+	Algorithm RateLimitAlgorithm
+	// Limit is number of the allowed requests per window.
+	// Optional. Default value 100.
+	Limit int
+	// Window is duration of the limit.
+	// Optional. Default value 1 minute.
+	Window time.Duration
+	// Burst is bucket size of the token bucket algorithm.
+	// Optional. Default value is the limit.
+	Burst int
+	// KeyFn defines a function to extract the rate limit key, see `RateLimitKeyByIP`, `RateLimitKeyByTrustedIP`,
+	// `RateLimitKeyBySubject`, `RateLimitKeyByAPIKey` and `RateLimitKeys`.
+	// Optional. Default value RateLimitKeyByIP.
+	KeyFn RateLimitKeyFunc
+	// KeyPrefix is prefix of the store key, so limiters are able to share the store.
+	// Optional. Default value `ratelimit`.
+	KeyPrefix string
+	// Store is storage of the rate limiter.
+	// Optional. Default value in-memory store of `NewRateLimitMemoryStore`.
+	Store RateLimitStore
+	// FailClosed denies the request with 503 when the store returns error,
+	// otherwise the request is allowed.
+	FailClosed bool
+	// DenyHandler defines a function which is executed when the request is denied, the rate limit headers
+	// have been written. It may be used to define a custom response.
+	// Optional. Default value writes 429 by `ApiResponse.ClientError`.
+	DenyHandler func(c qore.HttpContext, result RateLimitResult) error
+}
+
+// ErrRateLimitExceeded is the error of the denied request.
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+var (
+	errRateLimitKeyMissing     = errors.New("missing rate limit key")
+	errRateLimitSubjectMissing = errors.New("missing subject of the auth token")
+)
+
+// DefaultRateLimitConfig is RateLimit default config, 100 requests per minute of the client IP.
+var DefaultRateLimitConfig = &RateLimitConfig{
+	Algorithm: RATE_LIMIT_TOKEN_BUCKET,
+	Limit:     100,
+	Window:    time.Minute,
+	KeyPrefix: "ratelimit",
+}
+
+// rateLimitDefaultStore is the store of `RateLimit`, the middleware is applied per request.
+var rateLimitDefaultStore = sync.OnceValue(func() RateLimitStore { return NewRateLimitMemoryStore(0) })
+
+// RateLimitKeyByIP returns `RateLimitKeyFunc` that extracts the key from the client IP of `qore.HttpContext.RealIP`,
+// so the client IP is resolved the same way as the HTTP(s) server does. Use `RateLimitKeyByTrustedIP` to resolve
+// the client IP by the trusted proxies instead.
+func RateLimitKeyByIP() RateLimitKeyFunc {
+	return func(c qore.HttpContext) (string, error) {
+		ip := c.RealIP()
+		if ip == "" {
+			return "", errRateLimitKeyMissing
+		}
+		return "ip:" + ip, nil
+	}
+}
+
+// RateLimitKeyByTrustedIP returns `RateLimitKeyFunc` that extracts the key from the client IP.
+// The client-supplied `X-Forwarded-For` & `X-Real-IP` request headers are not trusted, so the key is the remote address,
+// unless the request comes from the trusted proxies (IP or CIDR, e.g. `10.0.0.0/8`). It returns error when
+// the trusted proxy is invalid.
+//
+//	keyFn, err := httpmw.RateLimitKeyByTrustedIP("10.0.0.0/8", "192.168.1.10")
+func RateLimitKeyByTrustedIP(trustedProxies ...string) (RateLimitKeyFunc, error) {
+	extractor, err := rateLimitIPExtractor(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit key by trusted IP: %w", err)
+	}
+	return func(c qore.HttpContext) (string, error) {
+		ip := extractor(c.Request())
+		if ip == "" {
+			return "", errRateLimitKeyMissing
+		}
+		return "ip:" + ip, nil
+	}, nil
+}
+
+// rateLimitIPExtractor returns `echo.IPExtractor` that trusts the request headers of the trusted proxies only,
+// the loopback, link-local & private network are not trusted unless they are given.
+func rateLimitIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	var options []echo.TrustOption
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			proxy = fmt.Sprintf("%s/%d", ip.String(), bits)
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	if len(options) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options = append(options, echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false))
+	fromXFF, fromRealIP := echo.ExtractIPFromXFFHeader(options...), echo.ExtractIPFromRealIPHeader(options...)
+	return func(req *http.Request) string {
+		if req.Header.Get(echo.HeaderXForwardedFor) != "" {
+			return fromXFF(req)
+		}
+		return fromRealIP(req)
+	}, nil
+}
+
+// RateLimitKeyBySubject returns `RateLimitKeyFunc` that extracts the key from the JWT subject
+// stored under `qore.HTTP_CONTEXT_AUTH` by the Jwt middleware, so it must be used after the Jwt middleware.
+func RateLimitKeyBySubject() RateLimitKeyFunc {
+	return func(c qore.HttpContext) (string, error) {
+		var claims jwt.Claims
+		switch auth := c.Get(qore.HTTP_CONTEXT_AUTH).(type) {
+		case *jwt.Token:
+			claims = auth.Claims
+		case jwt.Claims:
+			claims = auth
+		}
+		if claims == nil {
+			return "", errRateLimitSubjectMissing
+		}
+		sub, err := claims.GetSubject()
+		if err != nil || sub == "" {
+			return "", errRateLimitSubjectMissing
+		}
+		return "sub:" + sub, nil
+	}
+}
+
+// RateLimitKeyByAPIKey returns `RateLimitKeyFunc` that extracts the key from the API key,
+// the lookup format is the same as `JwtConfig.TokenLookup`, e.g. `header:X-API-Key` or `query:api_key`.
+// The API key is hashed, so the secret is not kept by the store. It returns error when the lookup is empty or invalid.
+//
+//	keyFn, err := httpmw.RateLimitKeyByAPIKey("header:X-API-Key,query:api_key")
+func RateLimitKeyByAPIKey(lookup string) (RateLimitKeyFunc, error) {
+	extractors, err := JwtExtractor(lookup)
+	if err == nil && len(extractors) == 0 {
+		err = errors.New("empty lookup")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rate limit key by API key: %w", err)
+	}
+	return func(c qore.HttpContext) (string, error) {
+		for _, extractor := range extractors {
+			values, err := extractor(c)
+			if err != nil || len(values) == 0 || values[0] == "" {
+				continue
+			}
+			sum := sha256.Sum256([]byte(values[0]))
+			return "key:" + hex.EncodeToString(sum[:16]), nil
+		}
+		return "", errRateLimitKeyMissing
+	}, nil
+}
+
+// RateLimitKeys returns `RateLimitKeyFunc` that returns the first key extracted by given functions,
+// e.g. `RateLimitKeys(RateLimitKeyBySubject(), RateLimitKeyByIP())` limits the anonymous request by IP.
+func RateLimitKeys(fns ...RateLimitKeyFunc) RateLimitKeyFunc {
+	return func(c qore.HttpContext) (string, error) {
+		err := errRateLimitKeyMissing
+		for _, fn := range fns {
+			var key string
+			if key, err = fn(c); err == nil {
+				return key, nil
+			}
+		}
+		return "", err
+	}
+}
+
+// rateLimitSetHeaders writes `RateLimit-*` & `Retry-After` headers of the result.
+func rateLimitSetHeaders(c qore.HttpContext, rule RateLimitRule, result RateLimitResult) {
+	header := c.Response().Header()
+	header.Set(qore.HTTP_HEADER_RATE_LIMIT_LIMIT, strconv.Itoa(result.Limit))
+	header.Set(qore.HTTP_HEADER_RATE_LIMIT_REMAINING, strconv.Itoa(result.Remaining))
+	header.Set(qore.HTTP_HEADER_RATE_LIMIT_RESET, strconv.FormatInt(rateLimitSeconds(result.Reset), 10))
+	header.Set(qore.HTTP_HEADER_RATE_LIMIT_POLICY, fmt.Sprintf("%d;w=%d", rule.Limit, rateLimitSeconds(rule.Window)))
+	if !result.Allowed {
+		header.Set(qore.HTTP_HEADER_RETRY_AFTER, strconv.FormatInt(max(1, rateLimitSeconds(result.RetryAfter)), 10))
+	}
+}
+
+// rateLimitSeconds returns the duration in seconds that is rounded up.
+func rateLimitSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+func rateLimitHandler(next qore.HttpHandler, config *RateLimitConfig, store RateLimitStore) qore.HttpHandler {
+	skipper := config.Skipper
+	if skipper == nil {
+		skipper = DefaultSkipper
+	}
+	keyFn := config.KeyFn
+	if keyFn == nil {
+		keyFn = RateLimitKeyByIP()
+	}
+	prefix := config.KeyPrefix
+	if prefix == "" {
+		prefix = DefaultRateLimitConfig.KeyPrefix
+	}
+	rule := RateLimitRule{
+		Algorithm: config.Algorithm,
+		Limit:     config.Limit,
+		Window:    config.Window,
+		Burst:     config.Burst,
+	}
+	if rule.Algorithm == "" {
+		rule.Algorithm = DefaultRateLimitConfig.Algorithm
+	}
+	if rule.Limit <= 0 {
+		rule.Limit = DefaultRateLimitConfig.Limit
+	}
+	if rule.Window <= 0 {
+		rule.Window = DefaultRateLimitConfig.Window
+	}
+
+	return func(c qore.HttpContext) error {
+		if skipper(c) {
+			return next(c)
+		}
+
+		// The request without key is not limited.
+		key, err := keyFn(c)
+		if err != nil {
+			return next(c)
+		}
+
+		result, err := store.Allow(c.Request().Context(), prefix+":"+key, rule)
+		if err != nil {
+			if config.FailClosed {
+				return c.Api().ServerError(qore.HttpStatusServiceUnavailable, fmt.Errorf("rate limiter: %w", err)).Response()
+			}
+			return next(c)
+		}
+
+		rateLimitSetHeaders(c, rule, result)
+		if !result.Allowed {
+			if config.DenyHandler != nil {
+				return config.DenyHandler(c, result)
+			}
+			return c.Api().ClientError(qore.HttpStatusTooManyRequests, ErrRateLimitExceeded).Response()
+		}
+		return next(c)
+	}
+}
+
+// RateLimitWithConfig returns a rate limiter middleware with config.
+// See: `RateLimit()`.
+//
+//	keyFn, err := httpmw.RateLimitKeyByAPIKey("header:X-API-Key")
+//	if err != nil {
+//		return err
+//	}
+//	app.SetHttpMiddleware(httpmw.RateLimitWithConfig(&httpmw.RateLimitConfig{
+//		Algorithm: httpmw.RATE_LIMIT_SLIDING_WINDOW,
+//		Limit:     1000,
+//		Window:    time.Hour,
+//		KeyFn:     keyFn,
+//	}))
+func RateLimitWithConfig(config *RateLimitConfig) qore.HttpMiddleware {
+	if config == nil {
+		config = DefaultRateLimitConfig
+	}
+	store := config.Store
+	if store == nil {
+		store = NewRateLimitMemoryStore(0)
+	}
+	return func(next qore.HttpHandler) qore.HttpHandler {
+		return rateLimitHandler(next, config, store)
+	}
+}
+
+// RateLimit returns a rate limiter middleware that limits 100 requests per minute of the client IP
+// by the token bucket algorithm in the memory store.
+func RateLimit(next qore.HttpHandler) qore.HttpHandler {
+	return rateLimitHandler(next, DefaultRateLimitConfig, rateLimitDefaultStore())
+}
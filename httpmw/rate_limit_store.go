@@ -0,0 +1,221 @@
+package httpmw
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitAlgorithm defines algorithm of the rate limiter.
+type RateLimitAlgorithm string
+
+// Built-in algorithm of the rate limiter.
+const (
+	// RATE_LIMIT_TOKEN_BUCKET refills the bucket continuously by `Limit` tokens per `Window`
+	// and allows burst request up to `Burst` tokens.
+	RATE_LIMIT_TOKEN_BUCKET RateLimitAlgorithm = "TOKEN_BUCKET"
+	// RATE_LIMIT_SLIDING_WINDOW allows `Limit` requests in the sliding `Window`, the request count
+	// is weighted from the previous & current fixed window.
+	RATE_LIMIT_SLIDING_WINDOW RateLimitAlgorithm = "SLIDING_WINDOW"
+)
+
+// ErrRateLimitRuleInvalid is returned by the store when the rule limit or window is not positive.
+var ErrRateLimitRuleInvalid = errors.New("rate limit rule limit and window must be positive")
+
+// RateLimitRule defines the rate limit policy of a key.
+type RateLimitRule struct {
+	Algorithm RateLimitAlgorithm
+	// Limit is number of the allowed requests per window.
+	Limit int
+	// Window is duration of the limit.
+	Window time.Duration
+	// Burst is bucket size of the token bucket algorithm, zero value is the limit.
+	Burst int
+}
+
+// RateLimitResult defines the result of rate limit check.
+type RateLimitResult struct {
+	// Allowed is true when the request is allowed.
+	Allowed bool
+	// Limit is the request quota of the key.
+	Limit int
+	// Remaining is the remaining request quota of the key.
+	Remaining int
+	// Reset is the duration until the quota is fully restored.
+	Reset time.Duration
+	// RetryAfter is the duration to wait before the next request is allowed, it is set when the request is denied.
+	RetryAfter time.Duration
+}
+
+// RateLimitStore defines the storage of rate limiter, the store must check & consume the quota atomically.
+// Redis-like backend can implement the algorithms by script, so the quota is shared by the application instances.
+type RateLimitStore interface {
+	// Allow consumes a request quota of the key by the rule.
+	Allow(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error)
+}
+
+// rateLimitMemoryShards is default number of the memory store shards.
+const rateLimitMemoryShards = 64
+
+type rateLimitMemoryEntry struct {
+	// Token bucket state.
+	tokens float64
+	last   time.Time
+	// Sliding window state.
+	windowStart time.Time
+	previous    int
+	current     int
+	// expireAt is the time when the entry state is the same as the new entry.
+	expireAt time.Time
+}
+
+type rateLimitMemoryShard struct {
+	mu      sync.Mutex
+	entries map[string]*rateLimitMemoryEntry
+	sweepAt time.Time
+}
+
+// RateLimitMemoryStore is in-memory `RateLimitStore` that is sharded by the key to reduce lock contention.
+// The quota is not shared by the application instances, use the shared store for the scaled application.
+type RateLimitMemoryStore struct {
+	shards []*rateLimitMemoryShard
+	now    func() time.Time
+}
+
+// Compile time check `RateLimitMemoryStore` implements `RateLimitStore`.
+var _ RateLimitStore = (*RateLimitMemoryStore)(nil)
+
+// NewRateLimitMemoryStore creates in-memory rate limit store with given number of shards,
+// zero or negative shards is the default 64 shards.
+func NewRateLimitMemoryStore(shards int) *RateLimitMemoryStore {
+	if shards <= 0 {
+		shards = rateLimitMemoryShards
+	}
+	store := &RateLimitMemoryStore{shards: make([]*rateLimitMemoryShard, shards), now: time.Now}
+	for i := range store.shards {
+		store.shards[i] = &rateLimitMemoryShard{entries: make(map[string]*rateLimitMemoryEntry)}
+	}
+	return store
+}
+
+func (s *RateLimitMemoryStore) shard(key string) *rateLimitMemoryShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// Allow consumes a request quota of the key by the rule.
+func (s *RateLimitMemoryStore) Allow(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error) {
+	if rule.Limit <= 0 || rule.Window <= 0 {
+		return RateLimitResult{}, ErrRateLimitRuleInvalid
+	}
+	now := s.now()
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	// Remove the expired entries once per window, so the idle keys do not grow the memory.
+	if now.After(shard.sweepAt) {
+		for k, entry := range shard.entries {
+			if now.After(entry.expireAt) {
+				delete(shard.entries, k)
+			}
+		}
+		shard.sweepAt = now.Add(rule.Window)
+	}
+
+	entry, ok := shard.entries[key]
+	if !ok {
+		entry = &rateLimitMemoryEntry{tokens: float64(rule.burst()), last: now, windowStart: now.Truncate(rule.Window)}
+		shard.entries[key] = entry
+	}
+	if rule.Algorithm == RATE_LIMIT_SLIDING_WINDOW {
+		return entry.slidingWindow(now, rule), nil
+	}
+	return entry.tokenBucket(now, rule), nil
+}
+
+// burst returns the bucket size of the token bucket algorithm.
+func (r RateLimitRule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Limit
+}
+
+func (e *rateLimitMemoryEntry) tokenBucket(now time.Time, rule RateLimitRule) RateLimitResult {
+	burst := float64(rule.burst())
+	perToken := rule.Window / time.Duration(rule.Limit)
+	if perToken <= 0 {
+		perToken = time.Nanosecond
+	}
+
+	// Refill.
+	if elapsed := now.Sub(e.last); elapsed > 0 {
+		e.tokens = math.Min(burst, e.tokens+float64(elapsed)/float64(perToken))
+		e.last = now
+	}
+
+	result := RateLimitResult{Limit: rule.burst()}
+	if e.tokens >= 1 {
+		e.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - e.tokens) * float64(perToken))
+	}
+	result.Remaining = int(e.tokens)
+	result.Reset = time.Duration((burst - e.tokens) * float64(perToken))
+	e.expireAt = now.Add(result.Reset)
+	return result
+}
+
+func (e *rateLimitMemoryEntry) slidingWindow(now time.Time, rule RateLimitRule) RateLimitResult {
+	// Roll the fixed window.
+	start := now.Truncate(rule.Window)
+	switch windows := start.Sub(e.windowStart) / rule.Window; {
+	case windows == 1:
+		e.previous, e.current = e.current, 0
+	case windows > 1:
+		e.previous, e.current = 0, 0
+	}
+	e.windowStart = start
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(rule.Window)
+	count := float64(e.previous)*weight + float64(e.current)
+
+	result := RateLimitResult{Limit: rule.Limit, Reset: rule.Window - elapsed}
+	if count+1 <= float64(rule.Limit) {
+		e.current++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = e.slidingWindowRetryAfter(elapsed, rule)
+	}
+	result.Remaining = max(0, rule.Limit-int(math.Ceil(count)))
+	e.expireAt = start.Add(2 * rule.Window)
+	return result
+}
+
+// slidingWindowRetryAfter returns the duration until the weighted count allows one more request.
+func (e *rateLimitMemoryEntry) slidingWindowRetryAfter(elapsed time.Duration, rule RateLimitRule) time.Duration {
+	window := float64(rule.Window)
+	limit := float64(rule.Limit)
+
+	// The previous window weight is decreasing in the current window.
+	if free := limit - float64(e.current) - 1; free >= 0 && e.previous > 0 {
+		if wait := time.Duration(window*(1-free/float64(e.previous))) - elapsed; wait > 0 {
+			return wait
+		}
+	}
+
+	// The current window becomes the previous window.
+	wait := rule.Window - elapsed
+	if e.current > 0 {
+		wait += time.Duration(math.Max(0, window*(1-(limit-1)/float64(e.current))))
+	}
+	return wait
+}
@@ -0,0 +1,119 @@
+package httpmw
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newRateLimitTestStore(now *time.Time) *RateLimitMemoryStore {
+	store := NewRateLimitMemoryStore(4)
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestRateLimitTokenBucketOk(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newRateLimitTestStore(&now)
+	rule := RateLimitRule{Algorithm: RATE_LIMIT_TOKEN_BUCKET, Limit: 10, Window: 10 * time.Second, Burst: 3}
+
+	// Burst is allowed, then the bucket is empty.
+	for i := 0; i < 3; i++ {
+		result, err := store.Allow(context.Background(), "a", rule)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request %d expected allowed, got %+v %v", i, result, err)
+		}
+		if result.Remaining != 2-i {
+			t.Errorf("request %d expected remaining %d, got %d", i, 2-i, result.Remaining)
+		}
+	}
+	result, _ := store.Allow(context.Background(), "a", rule)
+	if result.Allowed || result.RetryAfter != time.Second {
+		t.Errorf("expected denied with retry after 1s, got %+v", result)
+	}
+
+	// Other key has its own bucket.
+	if result, _ := store.Allow(context.Background(), "b", rule); !result.Allowed {
+		t.Error("expected other key is allowed")
+	}
+
+	// One token is refilled per second.
+	now = now.Add(time.Second)
+	if result, _ := store.Allow(context.Background(), "a", rule); !result.Allowed {
+		t.Error("expected allowed after refill")
+	}
+	if result, _ := store.Allow(context.Background(), "a", rule); result.Allowed {
+		t.Error("expected denied after the refilled token is consumed")
+	}
+}
+
+func TestRateLimitSlidingWindowOk(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newRateLimitTestStore(&now)
+	rule := RateLimitRule{Algorithm: RATE_LIMIT_SLIDING_WINDOW, Limit: 4, Window: time.Minute}
+
+	for i := 0; i < 4; i++ {
+		if result, _ := store.Allow(context.Background(), "a", rule); !result.Allowed {
+			t.Fatalf("request %d expected allowed", i)
+		}
+	}
+	result, _ := store.Allow(context.Background(), "a", rule)
+	if result.Allowed || result.Remaining != 0 || result.Reset != time.Minute {
+		t.Errorf("expected denied in the full window, got %+v", result)
+	}
+
+	// Half of the next window, the previous window weight is 2 of 4 requests.
+	now = now.Add(90 * time.Second)
+	for i := 0; i < 2; i++ {
+		if result, _ := store.Allow(context.Background(), "a", rule); !result.Allowed {
+			t.Fatalf("request %d expected allowed in the sliding window", i)
+		}
+	}
+	result, _ = store.Allow(context.Background(), "a", rule)
+	if result.Allowed {
+		t.Fatal("expected denied in the sliding window")
+	}
+	if result.RetryAfter != 15*time.Second {
+		t.Errorf("expected retry after 15s, got %s", result.RetryAfter)
+	}
+	now = now.Add(result.RetryAfter)
+	if result, _ := store.Allow(context.Background(), "a", rule); !result.Allowed {
+		t.Error("expected allowed after retry after")
+	}
+
+	// Idle key is reset.
+	now = now.Add(time.Hour)
+	if result, _ := store.Allow(context.Background(), "a", rule); !result.Allowed || result.Remaining != 3 {
+		t.Errorf("expected reset quota, got %+v", result)
+	}
+}
+
+func TestRateLimitMemoryStoreConcurrentOk(t *testing.T) {
+	store := NewRateLimitMemoryStore(0)
+	rule := RateLimitRule{Algorithm: RATE_LIMIT_SLIDING_WINDOW, Limit: 50, Window: time.Hour}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result, _ := store.Allow(context.Background(), "a", rule); result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 50 {
+		t.Errorf("expected 50 allowed requests, got %d", allowed)
+	}
+	if _, err := store.Allow(context.Background(), "a", RateLimitRule{}); err != ErrRateLimitRuleInvalid {
+		t.Errorf("expected invalid rule error, got %v", err)
+	}
+}
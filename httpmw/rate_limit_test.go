@@ -0,0 +1,245 @@
+package httpmw
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/qoinlyid/qore"
+)
+
+// newRateLimitTestRequest returns GET /users request from the remote address.
+func newRateLimitTestRequest(remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+// rateLimitTestKey returns the key of the request by the key function that is called with the real `qore.HttpContext`.
+func rateLimitTestKey(keyFn RateLimitKeyFunc, req *http.Request, setup func(c qore.HttpContext)) (key string, err error) {
+	serveTest(newTestApp("/users", func(c qore.HttpContext) error {
+		setup(c)
+		key, err = keyFn(c)
+		return c.NoContent(http.StatusNoContent)
+	}), req)
+	return
+}
+
+// rateLimitTestFailedStore is the store that always fails.
+type rateLimitTestFailedStore struct{}
+
+func (rateLimitTestFailedStore) Allow(ctx context.Context, key string, rule RateLimitRule) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("store is down")
+}
+
+func TestRateLimitOk(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mw := RateLimitWithConfig(&RateLimitConfig{
+		Algorithm: RATE_LIMIT_SLIDING_WINDOW,
+		Limit:     2,
+		Window:    time.Minute,
+		Store:     newRateLimitTestStore(&now),
+	})
+	app := newTestApp("/users", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }, mw)
+
+	for i, remaining := range []string{"1", "0"} {
+		rec := serveTest(app, newRateLimitTestRequest("203.0.113.7:5000"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d expected allowed, got %d %s", i, rec.Code, rec.Body)
+		}
+		header := rec.Header()
+		if header.Get(qore.HTTP_HEADER_RATE_LIMIT_LIMIT) != "2" || header.Get(qore.HTTP_HEADER_RATE_LIMIT_REMAINING) != remaining ||
+			header.Get(qore.HTTP_HEADER_RATE_LIMIT_RESET) != "60" || header.Get(qore.HTTP_HEADER_RATE_LIMIT_POLICY) != "2;w=60" ||
+			header.Get(qore.HTTP_HEADER_RETRY_AFTER) != "" {
+			t.Errorf("request %d unexpected headers %v", i, header)
+		}
+	}
+
+	// The exceeded request is denied with the retry headers.
+	rec := serveTest(app, newRateLimitTestRequest("203.0.113.7:5000"))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get(qore.HTTP_HEADER_RETRY_AFTER) != "90" || rec.Header().Get(qore.HTTP_HEADER_RATE_LIMIT_REMAINING) != "0" {
+		t.Errorf("unexpected denied headers %v", rec.Header())
+	}
+
+	// Other client IP has its own quota.
+	if rec = serveTest(app, newRateLimitTestRequest("203.0.113.8:5000")); rec.Code != http.StatusOK {
+		t.Errorf("expected other client IP is allowed, got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRateLimitFailOk(t *testing.T) {
+	tests := []struct {
+		failClosed bool
+		status     int
+	}{
+		{false, http.StatusOK},
+		{true, http.StatusServiceUnavailable},
+	}
+	for _, test := range tests {
+		mw := RateLimitWithConfig(&RateLimitConfig{Store: rateLimitTestFailedStore{}, FailClosed: test.failClosed})
+		app := newTestApp("/users", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }, mw)
+		rec := serveTest(app, newRateLimitTestRequest("203.0.113.7:5000"))
+		if rec.Code != test.status {
+			t.Errorf("fail closed %v: expected %d, got %d", test.failClosed, test.status, rec.Code)
+		}
+		if rec.Header().Get(qore.HTTP_HEADER_RATE_LIMIT_LIMIT) != "" {
+			t.Errorf("fail closed %v: unexpected rate limit headers", test.failClosed)
+		}
+	}
+}
+
+func TestRateLimitResponseOk(t *testing.T) {
+	tests := []struct {
+		name        string
+		problem     bool
+		config      *RateLimitConfig
+		status      int
+		contentType string
+		body        map[string]any
+	}{
+		{"denied", false, &RateLimitConfig{Limit: 1, Store: NewRateLimitMemoryStore(0)},
+			http.StatusTooManyRequests, echo.MIMEApplicationJSON,
+			map[string]any{"success": false, "code": "429", "error": "rate limit exceeded"}},
+		{"fail closed", false, &RateLimitConfig{Store: rateLimitTestFailedStore{}, FailClosed: true},
+			http.StatusServiceUnavailable, echo.MIMEApplicationJSON,
+			map[string]any{"success": false, "code": "503", "error": "rate limiter: store is down"}},
+		{"denied problem", true, &RateLimitConfig{Limit: 1, Store: NewRateLimitMemoryStore(0)},
+			http.StatusTooManyRequests, qore.HTTP_MIME_PROBLEM_JSON,
+			map[string]any{"type": "about:blank", "title": "Too Many Requests", "status": 429.0, "detail": "rate limit exceeded"}},
+		{"fail closed problem", true, &RateLimitConfig{Store: rateLimitTestFailedStore{}, FailClosed: true},
+			http.StatusServiceUnavailable, qore.HTTP_MIME_PROBLEM_JSON,
+			map[string]any{"type": "about:blank", "title": "Service Unavailable", "status": 503.0, "detail": "rate limiter: store is down"}},
+	}
+	for _, test := range tests {
+		app := newTestApp("/users", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }, RateLimitWithConfig(test.config))
+		if test.problem {
+			app.SetApiResponseInterface(qore.ApiResponseProblemInterface{})
+		}
+
+		// The first request of the denied case consumes the quota.
+		rec := serveTest(app, newRateLimitTestRequest("203.0.113.7:5000"))
+		if test.status == http.StatusTooManyRequests {
+			rec = serveTest(app, newRateLimitTestRequest("203.0.113.7:5000"))
+		}
+		if rec.Code != test.status || rec.Header().Get(echo.HeaderContentType) != test.contentType {
+			t.Errorf("%s: unexpected response %d %s", test.name, rec.Code, rec.Header().Get(echo.HeaderContentType))
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for key, value := range test.body {
+			if body[key] != value {
+				t.Errorf("%s: expected %s %v, got %v", test.name, key, value, body[key])
+			}
+		}
+	}
+}
+
+func TestRateLimitKeyOk(t *testing.T) {
+	apiKeyFn, err := RateLimitKeyByAPIKey("header:X-API-Key,query:api_key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFn := RateLimitKeys(RateLimitKeyBySubject(), apiKeyFn, RateLimitKeyByIP())
+	tests := []struct {
+		name  string
+		setup func(c qore.HttpContext)
+		key   string
+	}{
+		{"subject token", func(c qore.HttpContext) {
+			c.Set(qore.HTTP_CONTEXT_AUTH, &jwt.Token{Claims: jwt.RegisteredClaims{Subject: "user-1"}})
+		}, "sub:user-1"},
+		{"subject claims", func(c qore.HttpContext) {
+			c.Set(qore.HTTP_CONTEXT_AUTH, jwt.MapClaims{"sub": "user-2"})
+		}, "sub:user-2"},
+		{"header API key", func(c qore.HttpContext) {
+			c.Request().Header.Set("X-API-Key", "secret")
+		}, "key:2bb80d537b1da3e38bd30361aa855686"},
+		{"query API key", func(c qore.HttpContext) {
+			c.Request().URL.RawQuery = "api_key=secret"
+		}, "key:2bb80d537b1da3e38bd30361aa855686"},
+		{"client IP", func(c qore.HttpContext) {}, "ip:203.0.113.7"},
+		{"client IP of the server", func(c qore.HttpContext) {
+			c.Request().Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+		}, "ip:198.51.100.1"},
+	}
+	for _, test := range tests {
+		key, err := rateLimitTestKey(keyFn, newRateLimitTestRequest("203.0.113.7:5000"), test.setup)
+		if err != nil || key != test.key {
+			t.Errorf("%s: expected key %s, got %s %v", test.name, test.key, key, err)
+		}
+	}
+
+	// The request without key is not limited.
+	mw := RateLimitWithConfig(&RateLimitConfig{Limit: 1, KeyFn: RateLimitKeyBySubject(), Store: NewRateLimitMemoryStore(0)})
+	app := newTestApp("/users", func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }, mw)
+	for range 3 {
+		rec := serveTest(app, newRateLimitTestRequest("203.0.113.7:5000"))
+		if rec.Code != http.StatusOK || rec.Header().Get(qore.HTTP_HEADER_RATE_LIMIT_LIMIT) != "" {
+			t.Errorf("expected request without key is not limited, got %d", rec.Code)
+		}
+	}
+}
+
+func TestRateLimitKeyByTrustedIPOk(t *testing.T) {
+	tests := []struct {
+		trustedProxies []string
+		remoteAddr     string
+		forwarded      string
+		realIP         string
+		key            string
+	}{
+		// The client-supplied header is not trusted by default.
+		{nil, "203.0.113.7:5000", "198.51.100.1", "", "ip:203.0.113.7"},
+		{nil, "10.0.0.2:5000", "", "198.51.100.1", "ip:10.0.0.2"},
+		{[]string{"10.0.0.0/8", " 192.168.1.10", ""}, "10.0.0.2:5000", "198.51.100.1", "", "ip:198.51.100.1"},
+		{[]string{"10.0.0.0/8", "192.168.1.10"}, "192.168.1.10:5000", "198.51.100.1, 10.1.2.3", "", "ip:198.51.100.1"},
+		{[]string{"10.0.0.0/8", "192.168.1.10"}, "10.0.0.2:5000", "", "198.51.100.1", "ip:198.51.100.1"},
+		{[]string{"10.0.0.0/8", "192.168.1.10"}, "192.168.1.11:5000", "198.51.100.1", "", "ip:192.168.1.11"},
+		{[]string{"10.0.0.0/8", "192.168.1.10"}, "203.0.113.7:5000", "", "198.51.100.1", "ip:203.0.113.7"},
+	}
+	for _, test := range tests {
+		req := newRateLimitTestRequest(test.remoteAddr)
+		if test.forwarded != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, test.forwarded)
+		}
+		if test.realIP != "" {
+			req.Header.Set(echo.HeaderXRealIP, test.realIP)
+		}
+		keyFn, err := RateLimitKeyByTrustedIP(test.trustedProxies...)
+		if err != nil {
+			t.Fatalf("%v: %v", test.trustedProxies, err)
+		}
+		key, err := rateLimitTestKey(keyFn, req, func(qore.HttpContext) {})
+		if err != nil || key != test.key {
+			t.Errorf("%v %s: expected key %s, got %s %v", test.trustedProxies, test.remoteAddr, test.key, key, err)
+		}
+	}
+}
+
+func TestRateLimitKeyConfigErr(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (RateLimitKeyFunc, error)
+	}{
+		{"invalid trusted proxy CIDR", func() (RateLimitKeyFunc, error) { return RateLimitKeyByTrustedIP("10.0.0.0/33") }},
+		{"invalid trusted proxy IP", func() (RateLimitKeyFunc, error) { return RateLimitKeyByTrustedIP("10.0.0.256") }},
+		{"invalid API key lookup", func() (RateLimitKeyFunc, error) { return RateLimitKeyByAPIKey("X-API-Key") }},
+		{"empty API key lookup", func() (RateLimitKeyFunc, error) { return RateLimitKeyByAPIKey("") }},
+	}
+	for _, test := range tests {
+		if keyFn, err := test.fn(); err == nil || keyFn != nil {
+			t.Errorf("%s: expected construction error, got %v", test.name, err)
+		}
+	}
+}
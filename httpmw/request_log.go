@@ -1,9 +1,15 @@
 package httpmw
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
 	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/qoinlyid/qore"
 )
 
@@ -25,7 +31,8 @@ type RequestLogConfig struct {
 	LogURI bool
 	// LogRoutePath instructs logger to extract route path part to which request was matched to (i.e. `/user/:id`)
 	LogRoutePath bool
-	// LogTraceID instructs logger to extract request ID from request `X-Trace-ID` header or response if request did not have value.
+	// LogTraceID instructs logger to extract trace ID from response `X-Trace-ID` header that is written by `TraceID` middleware,
+	// or the valid request `X-Trace-ID` header if response did not have value.
 	LogTraceID bool
 	// LogReferer instructs logger to extract request referer values.
 	LogReferer bool
@@ -34,12 +41,55 @@ type RequestLogConfig struct {
 	// LogStatus instructs logger to extract response status code. If handler chain returns an echo.HTTPError,
 	// the status code is extracted from the echo.HTTPError returned
 	LogStatus bool
+	// LogResponseSize instructs logger to extract response size in bytes.
+	LogResponseSize bool
+	// LogError instructs logger to extract error returned by the handler chain and the panic flag.
+	LogError bool
 	// LogHeaders instructs logger to extract given list of headers from request. Note: request can contain more than
 	// one header with same value so slice of values is been logger for each given header.
 	//
 	// Note: header values are converted to canonical form with http.CanonicalHeaderKey as this how request parser converts header
 	// names to. For example, the canonical key for "accept-encoding" is "Accept-Encoding".
 	LogHeaders []string
+
+	// LogRequestBody instructs logger to capture request body of the allowed content type.
+	LogRequestBody bool
+	// LogResponseBody instructs logger to capture response body of the allowed content type.
+	LogResponseBody bool
+	// BodyMaxSize is maximum captured body size in bytes, the rest of the body is truncated.
+	// Optional. Default value 4KB.
+	BodyMaxSize int
+	// BodyContentTypes is content type prefix allowlist of the captured body, the body of content type
+	// other than JSON & form (e.g. XML) is not redacted.
+	// Optional. Default value DefaultBodyContentTypes.
+	BodyContentTypes []string
+	// RedactFields is redaction rules of the JSON & form body field, the rule is the field name
+	// that matches the field at any depth (e.g. `password`) or the path from the root prefixed by `$`
+	// (e.g. `$.user.cards[*].number`) where `*` matches any field or array item. The field name is matched
+	// regardless of the case, `_` & `-`, so `access_token` matches `accessToken`.
+	// Optional. Default value DefaultRedactFields.
+	RedactFields []string
+	// RedactHeaders is redacted headers of the `LogHeaders`.
+	// Optional. Default value DefaultRedactHeaders.
+	RedactHeaders []string
+	// RedactMask is replacement of the redacted value.
+	// Optional. Default value `[REDACTED]`.
+	RedactMask string
+
+	// Skipper defines a function to skip the request log, see `SkipPaths`.
+	// Optional. Default value DefaultSkipper.
+	Skipper Skipper
+	// SampleRate is ratio of the non-error (1xx-3xx) request being logged in range (0, 1],
+	// the request with 4xx & 5xx status is always logged.
+	// Optional. Default value 0 logs every request.
+	SampleRate float64
+	// Logger writes the request log into a dedicated sink instead of the app logger, e.g. the access log file
+	// or `slog.New(handler)` of the custom `slog.Handler`. The record is the `RequestLog` message with the `traceId`
+	// attribute, the `request` & `response` groups and the level by the response status: info for 1xx-3xx,
+	// warn for 4xx and error for 5xx. The record is written regardless of `Config.LogLevel` of the app,
+	// it is filtered by the level of the given logger handler.
+	// Optional. Default value the logger of the HTTP context, see `HttpContext.Log()`.
+	Logger *slog.Logger
 }
 
 // DefaultRequestLogConfig is RequestLog default config.
@@ -53,93 +103,267 @@ var DefaultRequestLogConfig = &RequestLogConfig{
 	LogTraceID:      true,
 	LogUserAgent:    true,
 	LogStatus:       true,
+	LogResponseSize: true,
+	LogError:        true,
+}
+
+// SkipPaths returns `Skipper` that skips the request of given route path or URL path,
+// e.g. `SkipPaths("/healthz", "/metrics")`.
+func SkipPaths(paths ...string) Skipper {
+	return func(c qore.HttpContext) bool {
+		return slices.Contains(paths, c.Path()) || slices.Contains(paths, c.Request().URL.Path)
+	}
+}
+
+// requestLogStatus returns response status, the status of error that is not written yet
+// is extracted from the `echo.HTTPError` or 500.
+func requestLogStatus(c qore.HttpContext, err error, panicked bool) int {
+	res := c.Response()
+	switch {
+	case res.Committed:
+		return res.Status
+	case panicked:
+		return http.StatusInternalServerError
+	case err != nil:
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			return he.Code
+		}
+		return http.StatusInternalServerError
+	}
+	return res.Status
+}
+
+// requestLogBody holds captured request & response body.
+type requestLogBody struct {
+	contentTypes     []string
+	request          []byte
+	requestTruncated bool
+	requestErr       error
+	response         *captureWriter
 }
 
 func requestLogHandler(next qore.HttpHandler, config *RequestLogConfig) qore.HttpHandler {
 	if config == nil {
 		config = DefaultRequestLogConfig
 	}
+	skipper := config.Skipper
+	if skipper == nil {
+		skipper = DefaultSkipper
+	}
+	bodyMaxSize := config.BodyMaxSize
+	if bodyMaxSize <= 0 {
+		bodyMaxSize = defaultBodyMaxSize
+	}
+	bodyContentTypes := config.BodyContentTypes
+	if bodyContentTypes == nil {
+		bodyContentTypes = DefaultBodyContentTypes
+	}
+	redactFields, redactHeaders := config.RedactFields, config.RedactHeaders
+	if redactFields == nil {
+		redactFields = DefaultRedactFields
+	}
+	if redactHeaders == nil {
+		redactHeaders = DefaultRedactHeaders
+	}
+	redact := newRedactor(redactFields, redactHeaders, config.RedactMask)
 
 	return func(c qore.HttpContext) (e error) {
-		var (
-			reqArgs []any
-			resArgs []any
-		)
-		req := c.Request()
-		res := c.Response()
-		start := time.Now()
-		logger := c.Log()
-
-		// Check config [LogLatency].
-		if config.LogLatency {
-			latency := time.Since(start)
-			reqArgs = append(reqArgs, slog.Int64("latency", latency.Milliseconds()))
-			// Check config [LogLatencyHuman].
-			if config.LogLatencyHuman {
-				reqArgs = append(reqArgs, slog.String("latencyHuman", latency.String()))
-			}
-		}
-		// Check config [LogProtocol].
-		if config.LogProtocol {
-			reqArgs = append(reqArgs, slog.String("proto", req.Proto))
+		if skipper(c) {
+			return next(c)
 		}
-		// Check config [LogRemoteIP].
-		if config.LogRemoteIP {
-			reqArgs = append(reqArgs, slog.String("remoteIP", c.RealIP()))
-		}
-		// Check config [LogHost].
-		if config.LogHost {
-			reqArgs = append(reqArgs, slog.String("host", req.Host))
-		}
-		// Check config [LogMethod].
-		if config.LogMethod {
-			reqArgs = append(reqArgs, slog.String("method", req.Method))
-		}
-		// Check config [LogURI].
-		if config.LogURI {
-			reqArgs = append(reqArgs, slog.String("uri", req.RequestURI))
+
+		// Capture body.
+		body := requestLogBody{contentTypes: bodyContentTypes}
+		req, res := c.Request(), c.Response()
+		if config.LogRequestBody && bodyAllowed(req.Header.Get(echo.HeaderContentType), bodyContentTypes) {
+			body.request, body.requestTruncated, body.requestErr = captureRequestBody(req, bodyMaxSize)
 		}
-		// Check config [LogRoutePath].
-		if config.LogRoutePath {
-			reqArgs = append(reqArgs, slog.String("path", c.Path()))
+		if config.LogResponseBody {
+			body.response = &captureWriter{ResponseWriter: res.Writer, limit: bodyMaxSize}
+			res.Writer = body.response
+			defer func() { res.Writer = body.response.ResponseWriter }()
 		}
-		// Check config [LogTraceID].
-		if config.LogTraceID {
-			xtraceid := req.Header.Get(qore.HTTP_HEADER_TRACE_ID)
-			if xtraceid == "" {
-				xtraceid = res.Header().Get(qore.HTTP_HEADER_TRACE_ID)
+
+		start := time.Now()
+		panicked := true
+		defer func() {
+			// Re-panic after logging, so it is handled by the `Recover` middleware.
+			var recovered any
+			if panicked {
+				recovered = recover()
 			}
-			logger = logger.With("traceId", xtraceid)
+			requestLogWrite(c, config, redact, body, start, e, recovered)
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		e = next(c)
+		panicked = false
+		return e
+	}
+}
+
+// requestLogWrite logs the request after the handler chain is completed, the log level is
+// chosen by the status class, 2xx info, 4xx warn and 5xx error.
+func requestLogWrite(
+	c qore.HttpContext,
+	config *RequestLogConfig,
+	redact *redactor,
+	body requestLogBody,
+	start time.Time,
+	err error,
+	recovered any,
+) {
+	panicked := recovered != nil
+	if panicked && err == nil {
+		err, _ = recovered.(error)
+		if err == nil {
+			err = fmt.Errorf("%v", recovered)
 		}
-		// Check config [LogReferer].
-		if config.LogReferer {
-			reqArgs = append(reqArgs, slog.String("referer", req.Referer()))
+	}
+	status := requestLogStatus(c, err, panicked)
+	if status < http.StatusBadRequest && config.SampleRate > 0 && config.SampleRate < 1 &&
+		rand.Float64() >= config.SampleRate {
+		return
+	}
+
+	var (
+		reqArgs []any
+		resArgs []any
+		traceID string
+	)
+	req := c.Request()
+	res := c.Response()
+
+	// Check config [LogLatency].
+	if config.LogLatency {
+		latency := time.Since(start)
+		reqArgs = append(reqArgs, slog.Int64("latency", latency.Milliseconds()))
+		// Check config [LogLatencyHuman].
+		if config.LogLatencyHuman {
+			reqArgs = append(reqArgs, slog.String("latencyHuman", latency.String()))
 		}
-		// Check config [LogUserAgent].
-		if config.LogUserAgent {
-			reqArgs = append(reqArgs, slog.String("userAgent", req.UserAgent()))
+	}
+	// Check config [LogProtocol].
+	if config.LogProtocol {
+		reqArgs = append(reqArgs, slog.String("proto", req.Proto))
+	}
+	// Check config [LogRemoteIP].
+	if config.LogRemoteIP {
+		reqArgs = append(reqArgs, slog.String("remoteIP", c.RealIP()))
+	}
+	// Check config [LogHost].
+	if config.LogHost {
+		reqArgs = append(reqArgs, slog.String("host", req.Host))
+	}
+	// Check config [LogMethod].
+	if config.LogMethod {
+		reqArgs = append(reqArgs, slog.String("method", req.Method))
+	}
+	// Check config [LogURI].
+	if config.LogURI {
+		reqArgs = append(reqArgs, slog.String("uri", req.RequestURI))
+	}
+	// Check config [LogRoutePath].
+	if config.LogRoutePath {
+		reqArgs = append(reqArgs, slog.String("path", c.Path()))
+	}
+	// Check config [LogTraceID].
+	if config.LogTraceID {
+		traceID = res.Header().Get(qore.HTTP_HEADER_TRACE_ID)
+		if x := req.Header.Get(qore.HTTP_HEADER_TRACE_ID); traceID == "" && qore.ValidationIsTraceID(x) {
+			traceID = x
 		}
-		// Check config [LogStatus].
-		if config.LogStatus {
-			resArgs = append(resArgs, slog.Int("status", res.Status))
+	}
+	// Check config [LogReferer].
+	if config.LogReferer {
+		reqArgs = append(reqArgs, slog.String("referer", req.Referer()))
+	}
+	// Check config [LogUserAgent].
+	if config.LogUserAgent {
+		reqArgs = append(reqArgs, slog.String("userAgent", req.UserAgent()))
+	}
+	// Check config [LogStatus].
+	if config.LogStatus {
+		resArgs = append(resArgs, slog.Int("status", status))
+	}
+	// Check config [LogResponseSize].
+	if config.LogResponseSize {
+		resArgs = append(resArgs, slog.Int64("size", res.Size))
+	}
+	// Check config [LogError].
+	if config.LogError {
+		if err != nil {
+			resArgs = append(resArgs, slog.String("error", err.Error()))
 		}
-		// Check config [LogHeaders].
-		if len(config.LogHeaders) > 0 {
-			var x []any
-			for _, key := range config.LogHeaders {
-				if val := req.Header.Get(key); val != "" {
-					x = append(x, slog.String(key, val))
-				}
+		resArgs = append(resArgs, slog.Bool("panic", panicked))
+	}
+	// Check config [LogHeaders].
+	if len(config.LogHeaders) > 0 {
+		var x []any
+		for _, key := range config.LogHeaders {
+			if val := req.Header.Get(key); val != "" {
+				x = append(x, slog.String(key, redact.header(key, val)))
 			}
-			reqArgs = append(reqArgs, slog.Group("header", x...))
 		}
+		reqArgs = append(reqArgs, slog.Group("header", x...))
+	}
 
-		logger.Info(
-			"RequestLog",
-			slog.Group("request", reqArgs...),
-			slog.Group("response", resArgs...),
+	// Check config [LogRequestBody], the capture failure is logged instead of failing the request.
+	if body.requestErr != nil {
+		reqArgs = append(reqArgs, slog.String("bodyError", body.requestErr.Error()))
+	}
+	if body.request != nil {
+		reqArgs = append(reqArgs,
+			slog.String("body", redact.body(req.Header.Get(echo.HeaderContentType), body.request, body.requestTruncated)),
+			slog.Bool("bodyTruncated", body.requestTruncated),
 		)
-		return next(c)
+	}
+	// Check config [LogResponseBody].
+	if body.response != nil && body.response.buf.Len() > 0 {
+		contentType := res.Header().Get(echo.HeaderContentType)
+		if bodyAllowed(contentType, body.contentTypes) {
+			resArgs = append(resArgs,
+				slog.String("body", redact.body(contentType, body.response.buf.Bytes(), body.response.truncated)),
+				slog.Bool("bodyTruncated", body.response.truncated),
+			)
+		}
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	requestLogOutput(c, config.Logger, level, traceID, slog.Group("request", reqArgs...), slog.Group("response", resArgs...))
+}
+
+// requestLogOutput writes the request log with the level into the configured logger
+// or the logger of the HTTP context.
+func requestLogOutput(c qore.HttpContext, base *slog.Logger, level slog.Level, traceID string, args ...any) {
+	if base != nil {
+		if traceID != "" {
+			base = base.With("traceId", traceID)
+		}
+		base.Log(c.Request().Context(), level, "RequestLog", args...)
+		return
+	}
+
+	logger := c.Log()
+	if traceID != "" {
+		logger = logger.With("traceId", traceID)
+	}
+	switch level {
+	case slog.LevelError:
+		logger.Error("RequestLog", args...)
+	case slog.LevelWarn:
+		logger.Warn("RequestLog", args...)
+	default:
+		logger.Info("RequestLog", args...)
 	}
 }
 
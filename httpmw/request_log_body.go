@@ -0,0 +1,282 @@
+package httpmw
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactFields is default redaction rules of the request & response body, the field name is matched
+// regardless of the case, `_` & `-`, e.g. `access_token` matches `accessToken` & `Access-Token`.
+var DefaultRedactFields = []string{
+	"password", "password_confirmation", "token", "access_token", "refresh_token", "secret",
+	"pin", "otp", "nik", "card_number", "cvv",
+}
+
+// DefaultRedactHeaders is default redacted headers.
+var DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// DefaultBodyContentTypes is default content type allowlist of the body capture, it only has
+// the content types that are supported by the redaction (JSON & form).
+var DefaultBodyContentTypes = []string{
+	"application/json", "application/problem+json", "application/x-www-form-urlencoded",
+}
+
+const (
+	defaultBodyMaxSize = 4 << 10
+	defaultRedactMask  = "[REDACTED]"
+)
+
+// redactRule defines parsed redaction rule, the rule is the field name that matches the field
+// at any depth (e.g. `password`) or the path from the root prefixed by `$` (e.g. `$.user.cards[*].number`)
+// where `*` matches any field or array item.
+type redactRule struct {
+	path []string
+	any  bool
+}
+
+// redactor masks the field of the body & the header value.
+type redactor struct {
+	rules   []redactRule
+	keys    *regexp.Regexp
+	headers map[string]struct{}
+	mask    string
+}
+
+func newRedactor(fields, headers []string, mask string) *redactor {
+	if mask == "" {
+		mask = defaultRedactMask
+	}
+	r := &redactor{mask: mask, headers: make(map[string]struct{}, len(headers))}
+	for _, header := range headers {
+		r.headers[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	var names []string
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		rule := redactRule{any: !strings.HasPrefix(field, "$")}
+		path := strings.TrimPrefix(strings.TrimPrefix(field, "$"), ".")
+		path = strings.ReplaceAll(path, "[*]", ".*")
+		path = strings.ReplaceAll(path, "[", ".")
+		path = strings.ReplaceAll(path, "]", "")
+		for segment := range strings.SplitSeq(path, ".") {
+			if segment != "" {
+				rule.path = append(rule.path, redactKey(segment))
+			}
+		}
+		if len(rule.path) == 0 {
+			continue
+		}
+		r.rules = append(r.rules, rule)
+		if last := rule.path[len(rule.path)-1]; last != "*" {
+			names = append(names, redactKeyPattern(last))
+		}
+	}
+
+	// Key pattern is used to mask truncated JSON body that can not be parsed.
+	if len(names) > 0 {
+		r.keys = regexp.MustCompile(`(?i)("(?:` + strings.Join(names, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`)
+	}
+	return r
+}
+
+// redactKey returns the normalized field name, it is lowercased without `_` & `-`,
+// so the snake_case, kebab-case & camelCase field names are matched.
+func redactKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// redactKeyPattern returns the regexp pattern of the normalized field name that allows `_` & `-`
+// between the characters.
+func redactKeyPattern(key string) string {
+	chars := make([]string, 0, len(key))
+	for _, c := range key {
+		chars = append(chars, regexp.QuoteMeta(string(c)))
+	}
+	return strings.Join(chars, "[_-]?")
+}
+
+// header returns the header value or the mask for redacted header.
+func (r *redactor) header(key, value string) string {
+	if _, ok := r.headers[http.CanonicalHeaderKey(key)]; ok {
+		return r.mask
+	}
+	return value
+}
+
+// match returns true if the path matches any redaction rule.
+func (r *redactor) match(path []string) bool {
+	for _, rule := range r.rules {
+		if rule.any {
+			if len(path) >= len(rule.path) && segmentsMatch(rule.path, path[len(path)-len(rule.path):]) {
+				return true
+			}
+		} else if len(path) == len(rule.path) && segmentsMatch(rule.path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentsMatch(rule, path []string) bool {
+	for i := range rule {
+		if rule[i] != "*" && rule[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// walk masks the JSON value that matches the redaction rules.
+func (r *redactor) walk(v any, path []string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			childPath := append(path[:len(path):len(path)], redactKey(key))
+			if r.match(childPath) {
+				val[key] = r.mask
+				continue
+			}
+			val[key] = r.walk(child, childPath)
+		}
+	case []any:
+		for i, child := range val {
+			childPath := append(path[:len(path):len(path)], "*")
+			if r.match(childPath) {
+				val[i] = r.mask
+				continue
+			}
+			val[i] = r.walk(child, childPath)
+		}
+	}
+	return v
+}
+
+// body returns redacted body of the content type.
+func (r *redactor) body(contentType string, body []byte, truncated bool) string {
+	if len(r.rules) == 0 || len(body) == 0 {
+		return string(body)
+	}
+	switch contentType = strings.ToLower(contentType); {
+	case strings.Contains(contentType, "json"):
+		if v, ok := decodeJSONBody(body); !truncated && ok {
+			if data, err := json.Marshal(r.walk(v, nil)); err == nil {
+				return string(data)
+			}
+		}
+		if r.keys == nil {
+			return string(body)
+		}
+		return r.keys.ReplaceAllString(string(body), `${1}"`+r.mask+`"`)
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return string(body)
+		}
+		for key := range values {
+			if r.match([]string{redactKey(key)}) {
+				values[key] = []string{r.mask}
+			}
+		}
+		return values.Encode()
+	default:
+		return string(body)
+	}
+}
+
+// decodeJSONBody decodes the JSON body, the number is kept as `json.Number` so the large integer is not rounded.
+func decodeJSONBody(body []byte) (v any, ok bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	// The body must be a single JSON value.
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, false
+	}
+	return v, true
+}
+
+// bodyAllowed returns true if the content type is allowed to be captured.
+func bodyAllowed(contentType string, allowlist []string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, allowed := range allowlist {
+		if strings.HasPrefix(contentType, strings.ToLower(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// captureRequestBody reads up to limit bytes of the request body and restores the body,
+// so it can still be read fully by the handler. The body that failed to be read is restored with the read bytes,
+// so the handler reads the same bytes & error of the original body.
+func captureRequestBody(req *http.Request, limit int) (body []byte, truncated bool, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false, nil
+	}
+	body, err = io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	req.Body = &restoredBody{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(body) > limit {
+		return body[:limit], true, nil
+	}
+	return body, false, nil
+}
+
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter captures up to limit bytes of the response body.
+type captureWriter struct {
+	http.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if remain := w.limit - w.buf.Len(); remain > 0 {
+		if len(b) > remain {
+			w.buf.Write(b[:remain])
+			w.truncated = true
+		} else {
+			w.buf.Write(b)
+		}
+	} else if len(b) > 0 {
+		w.truncated = true
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
+
+// Unwrap returns the original response writer for `http.ResponseController`.
+func (w *captureWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
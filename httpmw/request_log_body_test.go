@@ -0,0 +1,94 @@
+package httpmw
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactorBodyOk(t *testing.T) {
+	r := newRedactor(append(DefaultRedactFields, "$.user.cards[*].number"), DefaultRedactHeaders, "")
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		truncated   bool
+		expected    string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `{"email":"a@b.c","password":"secret","user":{"nik":"3174","cards":[{"number":"4111","name":"A"}]}}`,
+			expected:    `{"email":"a@b.c","password":"[REDACTED]","user":{"cards":[{"name":"A","number":"[REDACTED]"}],"nik":"[REDACTED]"}}`,
+		},
+		{
+			name:        "truncated json",
+			contentType: "application/json",
+			body:        `{"token": "abc", "pin": 1234, "data": "x`,
+			truncated:   true,
+			expected:    `{"token": "[REDACTED]", "pin": "[REDACTED]", "data": "x`,
+		},
+		{
+			name:        "large number",
+			contentType: "application/json",
+			body:        `{"id":12345678901234567891,"amount":1.10,"pin":1234}`,
+			expected:    `{"amount":1.10,"id":12345678901234567891,"pin":"[REDACTED]"}`,
+		},
+		{
+			name:        "camelCase json",
+			contentType: "application/json",
+			body:        `{"accessToken":"a","refreshToken":"b","cardNumber":"4111","passwordConfirmation":"c","Access-Token":"d","tokens":1}`,
+			expected:    `{"Access-Token":"[REDACTED]","accessToken":"[REDACTED]","cardNumber":"[REDACTED]","passwordConfirmation":"[REDACTED]","refreshToken":"[REDACTED]","tokens":1}`,
+		},
+		{
+			name:        "truncated camelCase json",
+			contentType: "application/json",
+			body:        `{"accessToken": "abc", "card-number": 4111, "tokens": 1, "data": "x`,
+			truncated:   true,
+			expected:    `{"accessToken": "[REDACTED]", "card-number": "[REDACTED]", "tokens": 1, "data": "x`,
+		},
+		{
+			name:        "mixed case content type",
+			contentType: "Application/JSON; charset=UTF-8",
+			body:        `{"password":"secret"}`,
+			expected:    `{"password":"[REDACTED]"}`,
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `otp=123456&user=qore&refreshToken=abc`,
+			expected:    `otp=%5BREDACTED%5D&refreshToken=%5BREDACTED%5D&user=qore`,
+		},
+	}
+	for _, test := range tests {
+		if got := r.body(test.contentType, []byte(test.body), test.truncated); got != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, got)
+		}
+	}
+
+	if got := r.header("authorization", "Bearer x"); got != defaultRedactMask {
+		t.Errorf("expected redacted header, got %s", got)
+	}
+	if got := r.header("Accept", "application/json"); !strings.EqualFold(got, "application/json") {
+		t.Errorf("unexpected header %s", got)
+	}
+}
+
+func TestBodyAllowedOk(t *testing.T) {
+	tests := []struct {
+		contentType string
+		allowed     bool
+	}{
+		{"application/json; charset=utf-8", true},
+		{"application/problem+json", true},
+		{"application/x-www-form-urlencoded", true},
+		// The content type without redaction support is not captured by default.
+		{"application/xml", false},
+		{"text/plain", false},
+		{"multipart/form-data", false},
+	}
+	for _, test := range tests {
+		if got := bodyAllowed(test.contentType, DefaultBodyContentTypes); got != test.allowed {
+			t.Errorf("%s: expected allowed %v, got %v", test.contentType, test.allowed, got)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package httpmw
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/qoinlyid/qore"
+)
+
+// requestLogTestRecord is the captured request log.
+type requestLogTestRecord struct {
+	level slog.Level
+	attrs map[string]slog.Value
+}
+
+// requestLogTestHandler is `slog.Handler` that captures the request log, the group attribute is flattened.
+type requestLogTestHandler struct {
+	mu      *sync.Mutex
+	records *[]requestLogTestRecord
+	attrs   []slog.Attr
+}
+
+func (h *requestLogTestHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *requestLogTestHandler) Handle(_ context.Context, r slog.Record) error {
+	record := requestLogTestRecord{level: r.Level, attrs: make(map[string]slog.Value)}
+	add := func(attr slog.Attr) bool {
+		if attr.Value.Kind() != slog.KindGroup {
+			record.attrs[attr.Key] = attr.Value
+			return true
+		}
+		for _, child := range attr.Value.Group() {
+			record.attrs[attr.Key+"."+child.Key] = child.Value
+		}
+		return true
+	}
+	for _, attr := range h.attrs {
+		add(attr)
+	}
+	r.Attrs(add)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.records = append(*h.records, record)
+	return nil
+}
+
+func (h *requestLogTestHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append(slices.Clip(h.attrs), attrs...)
+	return &c
+}
+
+func (h *requestLogTestHandler) WithGroup(string) slog.Handler { return h }
+
+// list returns the captured request logs and resets them.
+func (h *requestLogTestHandler) list() []requestLogTestRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := *h.records
+	*h.records = nil
+	return records
+}
+
+// newRequestLogTestConfig returns copy of the config that writes the request log into the test handler.
+func newRequestLogTestConfig(config *RequestLogConfig) (*RequestLogConfig, *requestLogTestHandler) {
+	handler := &requestLogTestHandler{mu: new(sync.Mutex), records: new([]requestLogTestRecord)}
+	copied := *config
+	copied.Logger = slog.New(handler)
+	return &copied, handler
+}
+
+func TestRequestLogOk(t *testing.T) {
+	config, logs := newRequestLogTestConfig(DefaultRequestLogConfig)
+	mw := RequestLogWithConfig(config)
+	tests := []struct {
+		name    string
+		handler qore.HttpHandlerable
+		status  int64
+		level   slog.Level
+		err     string
+	}{
+		{"ok", func(c qore.HttpContext) error {
+			time.Sleep(2 * time.Millisecond)
+			return c.JSON(http.StatusOK, "ok")
+		}, http.StatusOK, slog.LevelInfo, ""},
+		{"written client error", func(c qore.HttpContext) error {
+			time.Sleep(2 * time.Millisecond)
+			return c.JSON(http.StatusNotFound, "not found")
+		}, http.StatusNotFound, slog.LevelWarn, ""},
+		{"http error", func(c qore.HttpContext) error {
+			time.Sleep(2 * time.Millisecond)
+			return echo.NewHTTPError(http.StatusTeapot, "teapot")
+		}, http.StatusTeapot, slog.LevelWarn, "code=418, message=teapot"},
+		{"error", func(c qore.HttpContext) error {
+			time.Sleep(2 * time.Millisecond)
+			return errors.New("failed")
+		}, http.StatusInternalServerError, slog.LevelError, "failed"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set(qore.HTTP_HEADER_TRACE_ID, "trace-1")
+		rec := serveTest(newTestApp("/users/:id", test.handler, mw), req)
+		if int64(rec.Code) != test.status {
+			t.Errorf("%s: expected response status %d, got %d", test.name, test.status, rec.Code)
+		}
+		records := logs.list()
+		if len(records) != 1 {
+			t.Fatalf("%s: expected 1 request log, got %d", test.name, len(records))
+		}
+		record := records[0]
+		if traceID := record.attrs["traceId"].String(); traceID != "trace-1" {
+			t.Errorf("%s: expected trace ID trace-1, got %s", test.name, traceID)
+		}
+		if record.level != test.level {
+			t.Errorf("%s: expected level %s, got %s", test.name, test.level, record.level)
+		}
+		if status := record.attrs["response.status"].Int64(); status != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, status)
+		}
+		if latency := record.attrs["request.latency"].Int64(); latency <= 0 {
+			t.Errorf("%s: expected positive latency, got %d", test.name, latency)
+		}
+		if path := record.attrs["request.path"].String(); path != "/users/:id" {
+			t.Errorf("%s: unexpected path %s", test.name, path)
+		}
+		if err, ok := record.attrs["response.error"]; test.err != "" && (!ok || err.String() != test.err) {
+			t.Errorf("%s: expected error %q, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestRequestLogPanicOk(t *testing.T) {
+	config, logs := newRequestLogTestConfig(DefaultRequestLogConfig)
+	app := newTestApp("/users/:id", func(c qore.HttpContext) error { panic("boom") }, RequestLogWithConfig(config))
+
+	recovered := func() (r any) {
+		defer func() { r = recover() }()
+		serveTest(app, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		return nil
+	}()
+	if recovered != "boom" {
+		t.Errorf("expected the panic is re-raised, got %v", recovered)
+	}
+	records := logs.list()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 request log, got %d", len(records))
+	}
+	record := records[0]
+	if record.level != slog.LevelError || record.attrs["response.status"].Int64() != http.StatusInternalServerError ||
+		!record.attrs["response.panic"].Bool() || record.attrs["response.error"].String() != "boom" {
+		t.Errorf("unexpected panic request log %v %v", record.level, record.attrs)
+	}
+}
+
+func TestRequestLogSkipOk(t *testing.T) {
+	config, logs := newRequestLogTestConfig(DefaultRequestLogConfig)
+	config.Skipper = SkipPaths("/healthz", "/users/:id")
+	config.SampleRate = 1e-9
+	mw := RequestLogWithConfig(config)
+
+	ok := func(c qore.HttpContext) error { return c.JSON(http.StatusOK, "ok") }
+	notFound := func(c qore.HttpContext) error { return c.JSON(http.StatusNotFound, "not found") }
+	serveTest(newTestApp("/healthz", ok, mw), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	serveTest(newTestApp("/users/:id", notFound, mw), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if records := logs.list(); len(records) != 0 {
+		t.Errorf("expected skipped paths are not logged, got %d logs", len(records))
+	}
+
+	// The sampled out request is not logged, the 4xx & 5xx request is always logged.
+	okApp, notFoundApp := newTestApp("/orders", ok, mw), newTestApp("/orders", notFound, mw)
+	for range 50 {
+		serveTest(okApp, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	}
+	for range 5 {
+		serveTest(notFoundApp, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	}
+	records := logs.list()
+	if len(records) != 5 {
+		t.Errorf("expected 5 request logs, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.attrs["response.status"].Int64() != http.StatusNotFound {
+			t.Errorf("unexpected sampled request log %v", record.attrs)
+		}
+	}
+}
+
+func TestRequestLogTraceIDOk(t *testing.T) {
+	config, logs := newRequestLogTestConfig(DefaultRequestLogConfig)
+	app := newTestApp("/users/:id", func(c qore.HttpContext) error {
+		return c.JSON(http.StatusOK, "ok")
+	}, RequestLogWithConfig(config), TraceID)
+	tests := []struct {
+		name     string
+		xTraceID string
+		valid    bool
+	}{
+		{"valid trace ID", "req-1", true},
+		{"rejected trace ID", "req 1\n", false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set(qore.HTTP_HEADER_TRACE_ID, test.xTraceID)
+		rec := serveTest(app, req)
+
+		// The logged trace ID is the trace ID of the response.
+		records := logs.list()
+		if len(records) != 1 {
+			t.Fatalf("%s: expected 1 request log, got %d", test.name, len(records))
+		}
+		traceID := records[0].attrs["traceId"].String()
+		if want := rec.Header().Get(qore.HTTP_HEADER_TRACE_ID); traceID == "" || traceID != want {
+			t.Errorf("%s: expected trace ID %s, got %s", test.name, want, traceID)
+		}
+		if (traceID == test.xTraceID) != test.valid {
+			t.Errorf("%s: unexpected trace ID %s", test.name, traceID)
+		}
+	}
+}
+
+// requestLogTestFailedBody is the request body that fails after the first bytes.
+type requestLogTestFailedBody struct {
+	read bool
+}
+
+func (b *requestLogTestFailedBody) Read(p []byte) (int, error) {
+	if b.read {
+		return 0, errors.New("connection reset")
+	}
+	b.read = true
+	return copy(p, `{"name":`), nil
+}
+
+func (b *requestLogTestFailedBody) Close() error { return nil }
+
+func TestRequestLogBodyCaptureErr(t *testing.T) {
+	config, logs := newRequestLogTestConfig(&RequestLogConfig{LogStatus: true, LogRequestBody: true})
+
+	// The request is handled with the original body.
+	var body []byte
+	var readErr error
+	app := newTestApp("/users", func(c qore.HttpContext) error {
+		body, readErr = io.ReadAll(c.Request().Body)
+		return c.JSON(http.StatusOK, "ok")
+	}, RequestLogWithConfig(config))
+	req := httptest.NewRequest(http.MethodPost, "/users", &requestLogTestFailedBody{})
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if rec := serveTest(app, req); rec.Code != http.StatusOK {
+		t.Fatalf("expected request is handled, got %d %s", rec.Code, rec.Body)
+	}
+	if string(body) != `{"name":` || readErr == nil {
+		t.Errorf("expected handler reads the original body & error, got %s %v", body, readErr)
+	}
+
+	// The capture failure is logged.
+	records := logs.list()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 request log, got %d", len(records))
+	}
+	if got := records[0].attrs["request.bodyError"].String(); got != "connection reset" {
+		t.Errorf("expected body capture error is logged, got %q", got)
+	}
+}
@@ -1,18 +1,69 @@
 package httpmw
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
 	"github.com/qoinlyid/qore"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// TraceID returns a X-TRACE-ID middleware.
+// TraceID returns a tracing middleware that accepts W3C `traceparent` & `tracestate` request headers,
+// creates OpenTelemetry span per request and emits the trace context in the response headers.
+// The span is propagated through the request context, see `qore.ContextFromHttp`.
+//
+// The `X-Trace-ID` header is kept for backward compatibility, it is the valid request header value
+// or the OpenTelemetry trace ID.
 func TraceID(next qore.HttpHandler) qore.HttpHandler {
 	return func(c qore.HttpContext) error {
-		xTraceID := c.Request().Header.Get(qore.HTTP_HEADER_TRACE_ID)
-		if xTraceID == "" {
+		req := c.Request()
+		ctx := qore.TraceExtract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := qore.Tracer().Start(ctx, req.Method+" "+c.Path(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("http.route", c.Path()),
+				attribute.String("url.path", req.URL.Path),
+				attribute.String("client.address", c.RealIP()),
+			),
+		)
+		defer span.End()
+
+		xTraceID := req.Header.Get(qore.HTTP_HEADER_TRACE_ID)
+		if qore.ValidationIsTraceID(xTraceID) {
+			span.SetAttributes(attribute.String("qore.trace_id", xTraceID))
+		} else if span.SpanContext().HasTraceID() {
+			xTraceID = span.SpanContext().TraceID().String()
+		} else {
 			xTraceID, _ = qore.StringAlphaNumRandom(32)
 		}
 		c.Set(qore.HTTP_CONTEXT_TRACE_ID, xTraceID)
 		c.Response().Header().Set(qore.HTTP_HEADER_TRACE_ID, xTraceID)
-		return next(c)
+		qore.TraceInject(ctx, propagation.HeaderCarrier(c.Response().Header()))
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+
+		// Response status, the error that is not written yet is handled by the HTTP error handler.
+		status := c.Response().Status
+		if err != nil && !c.Response().Committed {
+			status = http.StatusInternalServerError
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				status = he.Code
+			}
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if err != nil {
+			span.RecordError(err)
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		return err
 	}
 }
@@ -0,0 +1,116 @@
+package httpmw
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/qoinlyid/qore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordTraceID sets the global tracer provider that records the ended spans during the test.
+func recordTraceID(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+		_ = provider.Shutdown(t.Context())
+	})
+	return recorder
+}
+
+func TestTraceIDOk(t *testing.T) {
+	recorder := recordTraceID(t)
+	tests := []struct {
+		name        string
+		traceparent string
+		xTraceID    string
+		handler     qore.HttpHandlerable
+		status      int
+		code        codes.Code
+	}{
+		{"new trace", "", "", func(c qore.HttpContext) error {
+			return c.JSON(http.StatusOK, "ok")
+		}, http.StatusOK, codes.Unset},
+		{"inherited trace", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "req-1", func(c qore.HttpContext) error {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}, http.StatusNotFound, codes.Unset},
+		{"invalid trace ID", "", "req 1\n", func(c qore.HttpContext) error {
+			return errors.New("failed")
+		}, http.StatusInternalServerError, codes.Error},
+	}
+	for _, test := range tests {
+		recorder.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		if test.traceparent != "" {
+			req.Header.Set("traceparent", test.traceparent)
+		}
+		if test.xTraceID != "" {
+			req.Header.Set(qore.HTTP_HEADER_TRACE_ID, test.xTraceID)
+		}
+		var handlerSpan trace.SpanContext
+		var handlerTraceID any
+		rec := serveTest(newTestApp("/users/:id", func(c qore.HttpContext) error {
+			handlerSpan = trace.SpanContextFromContext(c.Request().Context())
+			handlerTraceID = c.Get(qore.HTTP_CONTEXT_TRACE_ID)
+			return test.handler(c)
+		}, TraceID), req)
+		if rec.Code != test.status {
+			t.Errorf("%s: expected response status %d, got %d", test.name, test.status, rec.Code)
+		}
+
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("%s: expected 1 span, got %d", test.name, len(spans))
+		}
+		span := spans[0]
+		if span.Name() != "GET /users/:id" || span.SpanKind() != trace.SpanKindServer || span.Status().Code != test.code {
+			t.Errorf("%s: unexpected span %s %s %v", test.name, span.Name(), span.SpanKind(), span.Status())
+		}
+		if !handlerSpan.Equal(span.SpanContext()) {
+			t.Errorf("%s: expected span is propagated through the request context", test.name)
+		}
+		var status int64
+		for _, attr := range span.Attributes() {
+			if attr.Key == attribute.Key("http.response.status_code") {
+				status = attr.Value.AsInt64()
+			}
+		}
+		if status != int64(test.status) {
+			t.Errorf("%s: expected status attribute %d, got %d", test.name, test.status, status)
+		}
+
+		traceID := span.SpanContext().TraceID().String()
+		if test.traceparent != "" && !strings.Contains(test.traceparent, traceID) {
+			t.Errorf("%s: expected trace ID of traceparent, got %s", test.name, traceID)
+		}
+		want := "00-" + traceID + "-" + span.SpanContext().SpanID().String() + "-01"
+		if got := rec.Header().Get("traceparent"); got != want {
+			t.Errorf("%s: expected traceparent %s, got %s", test.name, want, got)
+		}
+
+		// X-Trace-ID is the valid request header value or the trace ID.
+		xTraceID := traceID
+		if qore.ValidationIsTraceID(test.xTraceID) {
+			xTraceID = test.xTraceID
+		}
+		if got := rec.Header().Get(qore.HTTP_HEADER_TRACE_ID); got != xTraceID || handlerTraceID != xTraceID {
+			t.Errorf("%s: expected X-Trace-ID %s, got %s", test.name, xTraceID, got)
+		}
+	}
+}
@@ -1,56 +1,495 @@
 package qore
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
-func (app *App) startServer(listeners ...net.Listener) {
+// startModules executes `ModuleStarter` in module-load order and stops at the first failure,
+// the already started modules are stopped in reverse order and the error is returned.
+func (app *App) startModules(logger *logger) error {
+	app.modulesStarted = 0
+	for _, module := range app.modules {
+		if m, ok := module.(ModuleStarter); ok {
+			if err := m.OnStart(app.lifecycleCtx); err != nil {
+				app.stopModules(logger)
+				return fmt.Errorf("module %T failed to start: %w", module, err)
+			}
+		}
+		app.modulesStarted++
+	}
+	return nil
+}
+
+// readyModules executes `ModuleReadier` in module-load order.
+func (app *App) readyModules() {
+	for _, module := range app.modules {
+		if m, ok := module.(ModuleReadier); ok {
+			m.OnReady()
+		}
+	}
+}
+
+// stopModules executes `ModuleStopper` of the started modules in reverse module-load order
+// and aggregates the errors, the module that never started is not stopped.
+func (app *App) stopModules(logger *logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.Config.ShutdownTimeout)*time.Second)
+	defer cancel()
+
+	var errs error
+	for i := app.modulesStarted - 1; i >= 0; i-- {
+		if m, ok := app.modules[i].(ModuleStopper); ok {
+			if err := m.OnStop(ctx); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("module %T failed to stop: %w", app.modules[i], err))
+			}
+		}
+	}
+	app.modulesStarted = 0
+	if errs != nil {
+		logger.Error(errs.Error())
+	}
+}
+
+// startServer starts the modules, workers and application servers. It returns error when a module failed to start,
+// the supervisor must stop the application by `stopServer` then `App.Start` exits the process with code 1.
+func (app *App) startServer(listeners ...net.Listener) error {
 	logger := app.Logger().Group("lifecycle.start")
 
-	// Given slice listener, the net listener should be handled by supervisor.
-	if len(listeners) > 0 {
-		for i, listener := range listeners {
-			if listener == nil {
+	// Module start hook.
+	app.lifecycleCtx, app.lifecycleCancel = context.WithCancel(context.Background())
+	if err := app.startModules(logger); err != nil {
+		app.startErr = err
+		logger.Error(fmt.Sprintf("application startup aborted: %s", err.Error()))
+		return err
+	}
+	app.startWorkers(app.lifecycleCtx)
+
+	// Given slice listener, the net listener should be handled by supervisor,
+	// the listener index must be match with the registered server index.
+	// Empty slice of listener from supervisor, so should be handled manually.
+	for i, entry := range app.servers {
+		lfn := listenTCP(entry.address)
+		if len(listeners) > 0 {
+			if i >= len(listeners) || listeners[i] == nil {
+				logger.Warn(fmt.Sprintf("no listener from supervisor for %s server", entry.name))
 				continue
 			}
+			listener := listeners[i]
+			lfn = func() (net.Listener, error) { return listener, nil }
+		}
+		entry.server.start(lfn, logger)
+	}
+	app.ready.Store(true)
+	app.readyModules()
+	return nil
+}
+
+func (app *App) stopServer() {
+	logger := app.Logger().Group("lifecycle.stop")
+	app.ready.Store(false)
+
+	// Stoping application server in reverse order.
+	for i := len(app.servers) - 1; i >= 0; i-- {
+		app.servers[i].server.stop(logger)
+	}
+
+	// Background worker.
+	app.stopWorkers(logger)
+
+	// Module stop hook.
+	if app.lifecycleCancel != nil {
+		app.lifecycleCancel()
+	}
+	app.stopModules(logger)
+}
 
-			// Index 0 must be HTTP server listener.
-			if i == 0 && app.httpServer != nil {
-				app.httpServer.start(func() (listener net.Listener, err error) {
-					return listener, nil
-				}, logger)
+// dependencyPolicy returns open policy of given dependency,
+// fallback to the app-wide `Config.DependencyPolicy` then `DEPENDENCY_POLICY_DEGRADE`.
+func (app *App) dependencyPolicy(dependency Dependency) DependencyPolicy {
+	policy := app.Config.DependencyPolicy
+	if d, ok := dependency.(DependencyOpenPolicy); ok && !ValidationIsEmpty(string(d.OpenPolicy())) {
+		policy = d.OpenPolicy()
+	}
+	switch policy = DependencyPolicy(strings.ToUpper(string(policy))); policy {
+	case DEPENDENCY_POLICY_ABORT, DEPENDENCY_POLICY_RETRY:
+		return policy
+	default:
+		return DEPENDENCY_POLICY_DEGRADE
+	}
+}
+
+// dependencyTimeout returns the open or close timeout of dependency.
+// The close timeout is fallback to the `Config.ShutdownTimeout`.
+func (app *App) dependencyTimeout(closing bool) time.Duration {
+	if !closing {
+		return time.Duration(app.Config.DependencyOpenTimeout) * time.Second
+	}
+	if app.Config.DependencyCloseTimeout > 0 {
+		return time.Duration(app.Config.DependencyCloseTimeout) * time.Second
+	}
+	return time.Duration(app.Config.ShutdownTimeout) * time.Second
+}
+
+// dependencyCall is an open or close call of dependency that may outlive its deadline,
+// the dependency that does not implement `DependencyContext` is not able to be canceled.
+type dependencyCall struct {
+	done chan struct{}
+	err  error
+
+	mu        sync.Mutex
+	abandoned bool
+}
+
+// startDependencyCall opens or closes given dependency in the background.
+// The open call that is abandoned and succeeds late is closed, so the resource does not leak.
+func (app *App) startDependencyCall(ctx context.Context, dependency Dependency, closing bool) *dependencyCall {
+	op := "open"
+	fn := func(context.Context) error { return dependency.Open() }
+	if closing {
+		op = "close"
+		fn = func(context.Context) error { return dependency.Close() }
+	}
+	if d, ok := dependency.(DependencyContext); ok {
+		fn = d.OpenContext
+		if closing {
+			fn = d.CloseContext
+		}
+	}
+
+	call := &dependencyCall{done: make(chan struct{})}
+	if !closing {
+		app.dependencyLate.Add(1)
+	}
+	go func() {
+		err := TraceDependencyCall(ctx, dependency.Name(), op, fn)
+		call.mu.Lock()
+		call.err = err
+		close(call.done)
+		late := call.abandoned && err == nil
+		call.mu.Unlock()
+		if closing {
+			return
+		}
+		defer app.dependencyLate.Done()
+		if late {
+			app.closeLateDependency(dependency)
+		}
+	}()
+	return call
+}
+
+// wait waits the call result until the context is done, pending is true when the call is still running.
+func (call *dependencyCall) wait(ctx context.Context) (err error, pending bool) {
+	select {
+	case <-call.done:
+		return call.err, false
+	case <-ctx.Done():
+		return ctx.Err(), true
+	}
+}
+
+// abandon gives up the open call, the call that has succeeded or succeeds later is closed.
+func (call *dependencyCall) abandon(app *App, dependency Dependency) {
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	call.abandoned = true
+	select {
+	case <-call.done:
+		if call.err == nil {
+			app.dependencyLate.Add(1)
+			go func() {
+				defer app.dependencyLate.Done()
+				app.closeLateDependency(dependency)
+			}()
+		}
+	default:
+	}
+}
+
+// closeLateDependency closes the dependency that is opened after its open call was abandoned.
+func (app *App) closeLateDependency(dependency Dependency) {
+	app.Logger().Warn(fmt.Sprintf("dependency %s opened after it was given up, closing it", dependency.Name()))
+	ctx := context.Background()
+	if timeout := app.dependencyTimeout(true); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err, _ := app.startDependencyCall(ctx, dependency, true).wait(ctx); err != nil {
+		app.Logger().Error(fmt.Sprintf("dependency %s failed to close: %s", dependency.Name(), err.Error()))
+	}
+}
+
+// openDependency opens given dependency within the open timeout and retry it with exponential backoff
+// when the dependency policy is `DEPENDENCY_POLICY_RETRY`, the attempts are stopped when the context is done.
+// The attempt that is still running after the last attempt is abandoned, see `openDependencyCall`.
+func (app *App) openDependency(ctx context.Context, dependency Dependency, policy DependencyPolicy) error {
+	call, err := app.openDependencyCall(ctx, dependency, policy)
+	if call != nil {
+		call.abandon(app, dependency)
+	}
+	return err
+}
+
+// openDependencyCall opens given dependency like `openDependency` but returns the call that is still running
+// after the last attempt instead of abandoning it, so the caller may wait it later. The attempt that timed out
+// is waited by the next attempt instead of opening the dependency again, so the dependency is never opened concurrently.
+// The timed out attempt of `DependencyContext` dependency is canceled, so it is opened again by the next attempt
+// once the canceled call returns.
+func (app *App) openDependencyCall(
+	parent context.Context, dependency Dependency, policy DependencyPolicy,
+) (call *dependencyCall, err error) {
+	attempts := 1
+	if policy == DEPENDENCY_POLICY_RETRY && app.Config.DependencyRetryMax > 0 {
+		attempts += app.Config.DependencyRetryMax
+	}
+	timeout := app.dependencyTimeout(false)
+	backoff := time.Duration(app.Config.DependencyRetryBackoff) * time.Millisecond
+	_, cancelable := dependency.(DependencyContext)
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		ctx, cancel := parent, context.CancelFunc(func() {})
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(parent, timeout)
+		}
+		if call != nil && cancelable {
+			if err, pending := call.wait(ctx); !pending {
+				if err == nil {
+					cancel()
+					return nil, nil
+				}
+				call = nil
+			}
+		}
+		if call == nil {
+			call = app.startDependencyCall(ctx, dependency, false)
+		}
+		var pending bool
+		err, pending = call.wait(ctx)
+		cancel()
+		if err == nil {
+			return nil, nil
+		}
+		if pending {
+			if parent.Err() != nil {
+				return call, fmt.Errorf("open canceled: %w", err)
+			}
+			err = fmt.Errorf("open timed out after %s: %w", timeout, err)
+			app.Logger().Error(fmt.Sprintf("dependency %s open timed out after %s", dependency.Name(), timeout))
+		} else {
+			call = nil
+		}
+		if attempt == attempts {
+			break
+		}
+		app.Logger().Warn(fmt.Sprintf(
+			"dependency %s failed to open (attempt %d/%d), retrying in %s: %s",
+			dependency.Name(), attempt, attempts, backoff, err.Error(),
+		))
+		select {
+		case <-parent.Done():
+			return call, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return call, err
+}
+
+// openDependencies opens registered dependency based on dependency graph.
+// Dependencies in the same batch are opened in parallel.
+// It returns error when the startup must be aborted, the already opened dependencies
+// should be closed by `closeDependencies`.
+func (app *App) openDependencies() error {
+	if len(app.dependencyRegistry) == 0 {
+		return nil
+	}
+	batches, err := dependencyGraph(app.dependencyRegistry)
+	if err != nil {
+		return fmt.Errorf("failed to resolve dependency graph: %w", err)
+	}
+
+	app.dependencyMu.Lock()
+	app.dependencyOpened = make([]Dependency, 0, len(app.dependencyRegistry))
+	app.dependencyDegraded = make(map[string]error)
+	app.dependencyMu.Unlock()
+	for _, batch := range batches {
+		errs := make([]error, len(batch))
+		var wg sync.WaitGroup
+		for i, dependency := range batch {
+			// Required dependency must be available.
+			if d, ok := dependency.(DependencyDependsOn); ok {
+				for _, name := range d.DependsOn() {
+					if e, degraded := app.dependencyDegradedError(name); degraded {
+						errs[i] = fmt.Errorf("%w: %s: %w", ErrDependencyUnavailable, name, e)
+						break
+					}
+				}
 			}
+			if errs[i] != nil {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = app.openDependency(context.Background(), dependency, app.dependencyPolicy(dependency))
+			}()
+		}
+		wg.Wait()
+
+		var abort error
+		for i, dependency := range batch {
+			if errs[i] != nil {
+				err := fmt.Errorf("dependency %s failed to open: %w", dependency.Name(), errs[i])
+				if app.dependencyPolicy(dependency) == DEPENDENCY_POLICY_DEGRADE {
+					app.dependencyMu.Lock()
+					app.dependencyDegraded[dependency.Name()] = errs[i]
+					app.dependencyMu.Unlock()
+					app.Logger().Warn(fmt.Sprintf("%s, starting in degraded mode", err.Error()))
+					continue
+				}
+				app.Logger().Error(err.Error())
+				abort = errors.Join(abort, err)
+				continue
+			}
+			app.dependencyMu.Lock()
+			app.dependencyOpened = append(app.dependencyOpened, dependency)
+			app.dependencyMu.Unlock()
+			app.Logger().Debug(fmt.Sprintf("dependency %s has been opened successfully", dependency.Name()))
+		}
+		if abort != nil {
+			return abort
 		}
+	}
+	return nil
+}
+
+// dependencyDegradedError returns the open error of the degraded dependency.
+func (app *App) dependencyDegradedError(name string) (err error, degraded bool) {
+	app.dependencyMu.RLock()
+	defer app.dependencyMu.RUnlock()
+	err, degraded = app.dependencyDegraded[name]
+	return
+}
+
+// reopenDegradedDependencies opens the degraded dependency again in the dependency graph order,
+// the dependency that is opened is moved into the opened dependencies, so it is probed by the health check.
+// The dependency that depends on the degraded dependency waits until the required dependency is opened.
+// The open call that is still running is kept in `pending` and the dependency is skipped until the call returns,
+// so it is never opened concurrently and the monitor is not blocked when the context is done.
+func (app *App) reopenDegradedDependencies(ctx context.Context, pending map[string]*dependencyCall, logger *logger) {
+	app.dependencyMu.RLock()
+	degraded := len(app.dependencyDegraded)
+	app.dependencyMu.RUnlock()
+	if degraded == 0 {
+		return
+	}
+	batches, err := dependencyGraph(app.dependencyRegistry)
+	if err != nil {
 		return
 	}
 
-	// Empty slice of listener from supervisor, so should be handled manually.
-	// HTTP server.
-	if app.httpServer != nil {
-		app.httpServer.start(func() (listener net.Listener, err error) {
-			// Resolve TCP.
-			address := fmt.Sprintf(":%d", app.Config.HTTPPort)
-			addr, err := net.ResolveTCPAddr("tcp", address)
-			if err != nil {
-				return nil, fmt.Errorf("failed resolve listen %s: %w", address, err)
+	for _, batch := range batches {
+		for _, dependency := range batch {
+			if ctx.Err() != nil {
+				return
+			}
+			name := dependency.Name()
+			if _, degraded := app.dependencyDegradedError(name); !degraded {
+				continue
+			}
+			if d, ok := dependency.(DependencyDependsOn); ok && slices.ContainsFunc(d.DependsOn(), func(name string) bool {
+				_, degraded := app.dependencyDegradedError(name)
+				return degraded
+			}) {
+				continue
+			}
+
+			// Previous call is still running, the succeeded call is taken as opened.
+			call, ok := pending[name]
+			if ok {
+				select {
+				case <-call.done:
+				default:
+					logger.Debug(fmt.Sprintf("degraded dependency %s is still opening", name))
+					continue
+				}
+				delete(pending, name)
+			}
+			var err error
+			if !ok || call.err != nil {
+				if call, err = app.openDependencyCall(ctx, dependency, DEPENDENCY_POLICY_DEGRADE); call != nil {
+					pending[name] = call
+				}
 			}
 
-			// Listen TCP.
-			listener, err = net.ListenTCP("tcp", addr)
+			app.dependencyMu.Lock()
 			if err != nil {
-				return nil, fmt.Errorf("failed to listen TCP %s: %w", address, err)
+				app.dependencyDegraded[name] = err
+			} else {
+				delete(app.dependencyDegraded, name)
+				app.dependencyOpened = append(app.dependencyOpened, dependency)
 			}
-			return
-		}, logger)
+			app.dependencyMu.Unlock()
+			if err != nil {
+				logger.Debug(fmt.Sprintf("degraded dependency %s failed to open: %s", name, err.Error()))
+				continue
+			}
+			logger.Info(fmt.Sprintf("degraded dependency %s has been opened", name))
+		}
 	}
 }
 
-func (app *App) stopServer() {
-	logger := app.Logger().Group("lifecycle.stop")
+// closeDependencies closes opened dependency in the reverse opening order within a single shutdown deadline,
+// then waits the dependency that is opened late to be closed. The close is started only after the previous close
+// returns, so the dependency that is not closed before the deadline is logged and never closed.
+func (app *App) closeDependencies() {
+	ctx := context.Background()
+	if timeout := app.dependencyTimeout(true); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for i := len(app.dependencyOpened) - 1; i >= 0; i-- {
+		dependency := app.dependencyOpened[i]
+		if ctx.Err() != nil {
+			names := make([]string, 0, i+1)
+			for ; i >= 0; i-- {
+				names = append(names, app.dependencyOpened[i].Name())
+			}
+			app.Logger().Error(fmt.Sprintf(
+				"dependency %s not closed, the shutdown deadline is exceeded", strings.Join(names, ", "),
+			))
+			break
+		}
+		if err, pending := app.startDependencyCall(ctx, dependency, true).wait(ctx); err != nil {
+			if pending {
+				err = fmt.Errorf("close timed out: %w", err)
+			}
+			err = fmt.Errorf("dependency %s failed to close: %w", dependency.Name(), err)
+			app.Logger().Error(err.Error())
+			continue
+		}
+		app.Logger().Debug(fmt.Sprintf("dependency %s has been closed successfully", dependency.Name()))
+	}
+	app.dependencyOpened = nil
 
-	// Stoping HTTP server.
-	if app.httpServer != nil {
-		app.httpServer.stop(logger)
+	done := make(chan struct{})
+	go func() {
+		app.dependencyLate.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		app.Logger().Error("dependency that is opened late is not closed before the shutdown deadline")
 	}
 }
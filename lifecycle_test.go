@@ -0,0 +1,399 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// dependencyFailTest is a dependency that fails to open for the first `fails` attempts.
+type dependencyFailTest struct {
+	dependencyTest
+	fails  int32
+	policy DependencyPolicy
+	opens  atomic.Int32
+	closes atomic.Int32
+}
+
+func (d *dependencyFailTest) Open() error {
+	if d.opens.Add(1) <= d.fails {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (d *dependencyFailTest) Close() error {
+	d.closes.Add(1)
+	return nil
+}
+
+func (d *dependencyFailTest) OpenPolicy() DependencyPolicy { return d.policy }
+
+func newLifecycleTestApp(config *Config) *App {
+	app := &App{Config: config}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	return app
+}
+
+func TestDependencyPolicyDefaultOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{})
+	if policy := app.dependencyPolicy(&dependencyTest{name: "db"}); policy != DEPENDENCY_POLICY_DEGRADE {
+		t.Errorf("expected default policy %s, got %s", DEPENDENCY_POLICY_DEGRADE, policy)
+	}
+	if defaultConfig.DependencyPolicy != DEPENDENCY_POLICY_DEGRADE {
+		t.Errorf("expected default config policy %s, got %s", DEPENDENCY_POLICY_DEGRADE, defaultConfig.DependencyPolicy)
+	}
+}
+
+func TestDependencyPolicyRetryOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{DependencyRetryMax: 3, DependencyRetryBackoff: 1})
+
+	// Opened on the third attempt.
+	db := &dependencyFailTest{dependencyTest: dependencyTest{name: "db"}, fails: 2, policy: DEPENDENCY_POLICY_RETRY}
+	app.dependencyRegistry = []Dependency{db}
+	if err := app.openDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.opens.Load(); n != 3 {
+		t.Errorf("expected 3 open attempts, got %d", n)
+	}
+	if len(app.dependencyOpened) != 1 {
+		t.Errorf("expected opened dependency, got %d", len(app.dependencyOpened))
+	}
+
+	// Aborted after the retries are exhausted.
+	cache := &dependencyFailTest{dependencyTest: dependencyTest{name: "cache"}, fails: 10, policy: DEPENDENCY_POLICY_RETRY}
+	app.dependencyRegistry = []Dependency{cache}
+	if err := app.openDependencies(); err == nil {
+		t.Fatal("expected startup is aborted")
+	}
+	if n := cache.opens.Load(); n != 4 {
+		t.Errorf("expected 4 open attempts, got %d", n)
+	}
+}
+
+func TestDependencyPolicyDegradeOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{DependencyRetryMax: 3})
+	cache := &dependencyFailTest{dependencyTest: dependencyTest{name: "cache"}, fails: 1, policy: DEPENDENCY_POLICY_DEGRADE}
+	queue := &dependencyFailTest{dependencyTest: dependencyTest{name: "queue", dependsOn: []string{"cache"}}}
+	db := &dependencyFailTest{dependencyTest: dependencyTest{name: "db"}}
+	app.dependencyRegistry = []Dependency{cache, queue, db}
+
+	if err := app.openDependencies(); err != nil {
+		t.Fatalf("expected startup in degraded mode, got %v", err)
+	}
+	if n := cache.opens.Load(); n != 1 {
+		t.Errorf("expected degraded dependency is not retried, got %d attempts", n)
+	}
+	if _, ok := app.dependencyDegraded["cache"]; !ok {
+		t.Error("expected cache is degraded")
+	}
+	if _, ok := app.dependencyDegraded["queue"]; !ok {
+		t.Error("expected queue that depends on cache is degraded")
+	}
+	if n := queue.opens.Load(); n != 0 {
+		t.Errorf("expected queue is not opened, got %d attempts", n)
+	}
+	if len(app.dependencyOpened) != 1 || app.dependencyOpened[0] != db {
+		t.Errorf("expected only db is opened, got %v", app.dependencyOpened)
+	}
+}
+
+func TestDependencyPolicyAbortOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{DependencyPolicy: DEPENDENCY_POLICY_ABORT, DependencyRetryMax: 3})
+	config := &dependencyFailTest{dependencyTest: dependencyTest{name: "config", priority: 10}}
+	db := &dependencyFailTest{dependencyTest: dependencyTest{name: "db", dependsOn: []string{"config"}}, fails: 1}
+	cache := &dependencyFailTest{dependencyTest: dependencyTest{name: "cache", dependsOn: []string{"db"}}}
+	app.dependencyRegistry = []Dependency{config, db, cache}
+
+	if err := app.openDependencies(); err == nil {
+		t.Fatal("expected startup is aborted")
+	}
+	if n := db.opens.Load(); n != 1 {
+		t.Errorf("expected aborted dependency is not retried, got %d attempts", n)
+	}
+	if n := cache.opens.Load(); n != 0 {
+		t.Errorf("expected the next batch is not opened, got %d attempts", n)
+	}
+
+	// The already opened dependency is closed.
+	app.closeDependencies()
+	if n := config.closes.Load(); n != 1 {
+		t.Errorf("expected opened dependency is closed, got %d", n)
+	}
+	if n := db.closes.Load(); n != 0 {
+		t.Errorf("expected failed dependency is not closed, got %d", n)
+	}
+}
+
+// dependencyBlockTest is a dependency that blocks on open or close until it is released.
+type dependencyBlockTest struct {
+	dependencyTest
+	release    chan struct{}
+	blockClose bool
+	opens      atomic.Int32
+	running    atomic.Int32
+	concurrent atomic.Int32
+	closing    atomic.Bool
+	closes     atomic.Int32
+}
+
+func (d *dependencyBlockTest) Open() error {
+	d.opens.Add(1)
+	if n := d.running.Add(1); n > d.concurrent.Load() {
+		d.concurrent.Store(n)
+	}
+	defer d.running.Add(-1)
+	<-d.release
+	return nil
+}
+
+func (d *dependencyBlockTest) Close() error {
+	d.closing.Store(true)
+	if d.blockClose {
+		<-d.release
+	}
+	d.closes.Add(1)
+	return nil
+}
+
+func TestDependencyOpenTimeoutOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{
+		DependencyPolicy:       DEPENDENCY_POLICY_RETRY,
+		DependencyRetryMax:     1,
+		DependencyRetryBackoff: 1,
+		DependencyOpenTimeout:  1,
+		DependencyCloseTimeout: 1,
+	})
+	db := &dependencyBlockTest{dependencyTest: dependencyTest{name: "db"}, release: make(chan struct{})}
+	app.dependencyRegistry = []Dependency{db}
+
+	// The timed out attempt is waited by the retry instead of opening again.
+	if err := app.openDependencies(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected open timeout error, got %v", err)
+	}
+	if n := db.opens.Load(); n != 1 {
+		t.Errorf("expected 1 open call, got %d", n)
+	}
+	if n := db.concurrent.Load(); n != 1 {
+		t.Errorf("expected no concurrent open call, got %d", n)
+	}
+
+	// The late success is closed.
+	close(db.release)
+	app.closeDependencies()
+	if n := db.closes.Load(); n != 1 {
+		t.Errorf("expected the late opened dependency is closed, got %d", n)
+	}
+}
+
+// dependencyContextTest is a context-aware dependency that blocks the first open until its context is done.
+type dependencyContextTest struct {
+	dependencyTest
+	opens      atomic.Int32
+	concurrent atomic.Int32
+	running    atomic.Int32
+	canceled   atomic.Bool
+}
+
+func (d *dependencyContextTest) OpenContext(ctx context.Context) error {
+	defer d.running.Add(-1)
+	if n := d.running.Add(1); n > d.concurrent.Load() {
+		d.concurrent.Store(n)
+	}
+	if d.opens.Add(1) == 1 {
+		<-ctx.Done()
+		d.canceled.Store(true)
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (d *dependencyContextTest) CloseContext(ctx context.Context) error { return nil }
+
+func TestDependencyOpenContextRetryOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{
+		DependencyPolicy:       DEPENDENCY_POLICY_RETRY,
+		DependencyRetryMax:     1,
+		DependencyRetryBackoff: 1,
+		DependencyOpenTimeout:  1,
+	})
+	db := &dependencyContextTest{dependencyTest: dependencyTest{name: "db"}}
+	app.dependencyRegistry = []Dependency{db}
+
+	// The timed out attempt is canceled and opened again by the retry.
+	if err := app.openDependencies(); err != nil {
+		t.Fatalf("expected opened by the retry, got %v", err)
+	}
+	if !db.canceled.Load() {
+		t.Error("expected the timed out open call is canceled")
+	}
+	if n := db.opens.Load(); n != 2 {
+		t.Errorf("expected 2 open calls, got %d", n)
+	}
+	if n := db.concurrent.Load(); n != 1 {
+		t.Errorf("expected no concurrent open call, got %d", n)
+	}
+	if len(app.dependencyOpened) != 1 {
+		t.Errorf("expected opened dependency, got %d", len(app.dependencyOpened))
+	}
+}
+
+func TestDependencyCloseDeadlineOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{DependencyCloseTimeout: 1})
+	release := make(chan struct{})
+	defer close(release)
+	var dependencies []*dependencyBlockTest
+	for _, name := range []string{"db", "cache", "queue"} {
+		dependency := &dependencyBlockTest{dependencyTest: dependencyTest{name: name}, release: release, blockClose: true}
+		dependencies = append(dependencies, dependency)
+		app.dependencyOpened = append(app.dependencyOpened, dependency)
+	}
+
+	// All closes share a single deadline, the close is not started after the deadline.
+	start := time.Now()
+	app.closeDependencies()
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("expected closes within the shutdown deadline, took %s", elapsed)
+	}
+	if app.dependencyOpened != nil {
+		t.Error("expected opened dependencies are reset")
+	}
+	for _, dependency := range dependencies[:2] {
+		if dependency.closing.Load() {
+			t.Errorf("expected %s is not closed after the deadline", dependency.name)
+		}
+	}
+}
+
+// dependencySlowCloseTest is a dependency that records its close order after a delay.
+type dependencySlowCloseTest struct {
+	dependencyTest
+	delay time.Duration
+	mu    *sync.Mutex
+	calls *[]string
+}
+
+func (d *dependencySlowCloseTest) Close() error {
+	d.mu.Lock()
+	*d.calls = append(*d.calls, "start "+d.name)
+	d.mu.Unlock()
+	time.Sleep(d.delay)
+	d.mu.Lock()
+	*d.calls = append(*d.calls, "done "+d.name)
+	d.mu.Unlock()
+	return nil
+}
+
+func TestDependencyCloseOrderOk(t *testing.T) {
+	app := newLifecycleTestApp(&Config{DependencyCloseTimeout: 1})
+	var mu sync.Mutex
+	var calls []string
+	for _, name := range []string{"db", "cache"} {
+		app.dependencyOpened = append(app.dependencyOpened,
+			&dependencySlowCloseTest{dependencyTest: dependencyTest{name: name}, delay: 200 * time.Millisecond, mu: &mu, calls: &calls},
+		)
+	}
+
+	// The slow closes are not overlapped and run in the reverse opening order.
+	app.closeDependencies()
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"start cache", "done cache", "start db", "done db"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("call %d expected %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
+
+// moduleLifecycleTest is a module that records its lifecycle hook calls.
+type moduleLifecycleTest struct {
+	name  string
+	fail  bool
+	calls *[]string
+}
+
+func (m *moduleLifecycleTest) HttpRoutes(app *App) {}
+
+func (m *moduleLifecycleTest) OnStart(ctx context.Context) error {
+	*m.calls = append(*m.calls, "start "+m.name)
+	if m.fail {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func (m *moduleLifecycleTest) OnStop(ctx context.Context) error {
+	*m.calls = append(*m.calls, "stop "+m.name)
+	return nil
+}
+
+func TestModuleLifecycleOk(t *testing.T) {
+	var calls []string
+	app := newLifecycleTestApp(&Config{ShutdownTimeout: 1})
+	app.modules = []Module{
+		&moduleLifecycleTest{name: "a", calls: &calls},
+		&moduleLifecycleTest{name: "b", calls: &calls},
+	}
+	app.lifecycleCtx = context.Background()
+	if err := app.startModules(app.logger); err != nil {
+		t.Fatal(err)
+	}
+	app.stopModules(app.logger)
+	app.stopModules(app.logger)
+
+	expected := []string{"start a", "start b", "stop b", "stop a"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("call %d expected %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestModuleLifecycleStartErr(t *testing.T) {
+	var calls []string
+	app := newLifecycleTestApp(&Config{ShutdownTimeout: 1})
+	app.modules = []Module{
+		&moduleLifecycleTest{name: "a", calls: &calls},
+		&moduleLifecycleTest{name: "b", fail: true, calls: &calls},
+		&moduleLifecycleTest{name: "c", calls: &calls},
+	}
+
+	// The startup is aborted at the first failure, the supervisor stops without waiting the signal.
+	done := make(chan struct{})
+	go func() {
+		(&SupervisorNon{}).Run(app)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the supervisor returns on the aborted startup")
+	}
+
+	if app.startErr == nil {
+		t.Error("expected the startup error is kept")
+	}
+	if app.ready.Load() {
+		t.Error("expected the application is not ready")
+	}
+	expected := []string{"start a", "start b", "stop a"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("call %d expected %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package qore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsRegistry returns Prometheus registry of the application that is exposed by the metrics endpoint.
+// Application is able to register its own collector into the registry.
+//
+//	app.MetricsRegistry().MustRegister(ordersTotal)
+func (app *App) MetricsRegistry() *prometheus.Registry {
+	return app.metricsRegistry
+}
+
+// setupMetrics sets up Prometheus registry with build info, dependency health & readiness collectors.
+// Go runtime & process collectors are registered in `prometheus.DefaultRegisterer` that is gathered
+// by the metrics endpoint too.
+func (app *App) setupMetrics() {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewBuildInfoCollector(),
+		&appMetricsCollector{app: app},
+	)
+	app.metricsRegistry = registry
+}
+
+// setHttpMetricsRoute mounts Prometheus metrics endpoint into the HTTP(s) server.
+func (app *App) setHttpMetricsRoute() {
+	if !app.Config.HTTPMetricsEnabled || ValidationIsEmpty(app.Config.HTTPMetricsPath) || app.metricsRegistry == nil {
+		return
+	}
+	handler := promhttp.HandlerFor(prometheus.Gatherers{app.metricsRegistry, prometheus.DefaultGatherer}, promhttp.HandlerOpts{})
+	app.SetHttpRoutesOn(app.Config.HTTPMetricsServer, func(router *HttpRouter) {
+		router.Get(app.Config.HTTPMetricsPath, HttpHanlderChain(func(c HttpContext) error {
+			handler.ServeHTTP(c.Response(), c.Request())
+			return nil
+		}))
+	})
+}
+
+var (
+	metricsAppReadyDesc = prometheus.NewDesc(
+		"qore_app_ready",
+		"Whether the application is started and ready to serve the request (1) or not (0).",
+		[]string{"app", "version"}, nil,
+	)
+	metricsDependencyUpDesc = prometheus.NewDesc(
+		"qore_dependency_up",
+		"Whether the dependency is healthy (1) or not (0).",
+		[]string{"dependency", "critical"}, nil,
+	)
+	metricsDependencyStateDesc = prometheus.NewDesc(
+		"qore_dependency_state",
+		"Current health state of the dependency, the value is 1 for the current state.",
+		[]string{"dependency", "state"}, nil,
+	)
+	metricsDependencyTransitionsDesc = prometheus.NewDesc(
+		"qore_dependency_state_transitions",
+		"Number of the dependency state transitions in the flapping window.",
+		[]string{"dependency"}, nil,
+	)
+)
+
+// appMetricsCollector collects readiness & dependency health of the application. The dependency state
+// is taken from the background dependency monitor, so the scrape does not call the health check.
+type appMetricsCollector struct {
+	app *App
+}
+
+func (m *appMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metricsAppReadyDesc
+	ch <- metricsDependencyUpDesc
+	ch <- metricsDependencyStateDesc
+	ch <- metricsDependencyTransitionsDesc
+}
+
+func (m *appMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	app := m.app
+	ready := 0.0
+	if app.ready.Load() {
+		ready = 1
+	}
+	ch <- prometheus.MustNewConstMetric(metricsAppReadyDesc, prometheus.GaugeValue, ready,
+		app.Config.AppName, app.Config.AppVersion,
+	)
+
+	for _, dependency := range app.dependencyRegistry {
+		name := dependency.Name()
+		status := app.DependencyStatus(name)
+		if _, degraded := app.dependencyDegradedError(name); degraded {
+			status.State = DEPENDENCY_STATE_DEGRADED
+		}
+		critical := "false"
+		if dependencyCritical(dependency) {
+			critical = "true"
+		}
+
+		up := 0.0
+		if status.State == DEPENDENCY_STATE_HEALTHY {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(metricsDependencyUpDesc, prometheus.GaugeValue, up, name, critical)
+		ch <- prometheus.MustNewConstMetric(metricsDependencyStateDesc, prometheus.GaugeValue, 1, name, string(status.State))
+		ch <- prometheus.MustNewConstMetric(metricsDependencyTransitionsDesc, prometheus.GaugeValue,
+			float64(status.Transitions), name,
+		)
+	}
+}
@@ -0,0 +1,60 @@
+package qore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetricsEndpointOk(t *testing.T) {
+	app := &App{Config: &Config{
+		AppName:            "qore-test",
+		AppVersion:         "1.0.0",
+		HTTPMetricsEnabled: true,
+		HTTPMetricsServer:  HTTP_SERVER_ADMIN,
+		HTTPMetricsPath:    "/metrics",
+		DependencyPolicy:   DEPENDENCY_POLICY_ABORT,
+	}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	app.dependencyRegistry = []Dependency{
+		&dependencyTest{name: "db"},
+		&dependencyTest{name: "cache"},
+	}
+	app.dependencyDegraded = map[string]error{"cache": ErrHealthCheckNoStats}
+	app.dependencyMonitor.entries = map[string]*dependencyMonitorEntry{
+		"db": {state: DEPENDENCY_STATE_HEALTHY, since: time.Now()},
+	}
+	app.ready.Store(true)
+	if err := app.AddHttpServer(HTTP_SERVER_ADMIN, HttpServerConfig{Port: 3200}); err != nil {
+		t.Fatal(err)
+	}
+	app.setupMetrics()
+	app.setHttpMetricsRoute()
+	ordersTotal := prometheus.NewCounter(prometheus.CounterOpts{Name: "orders_total", Help: "Total number of the orders."})
+	app.MetricsRegistry().MustRegister(ordersTotal)
+	ordersTotal.Add(3)
+
+	rec := httptest.NewRecorder()
+	app.httpServers[HTTP_SERVER_ADMIN].core.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, metric := range []string{
+		`qore_app_ready{app="qore-test",version="1.0.0"} 1`,
+		`qore_dependency_up{critical="true",dependency="db"} 1`,
+		`qore_dependency_up{critical="true",dependency="cache"} 0`,
+		`qore_dependency_state{dependency="cache",state="DEGRADED"} 1`,
+		`orders_total 3`,
+		`go_goroutines `,
+		`go_memstats_heap_alloc_bytes `,
+	} {
+		if !strings.Contains(body, metric) {
+			t.Errorf("expected metric %s", metric)
+		}
+	}
+}
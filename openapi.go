@@ -0,0 +1,648 @@
+package qore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIDocument defines OpenAPI 3.1 document.
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+// OpenAPIInfo defines OpenAPI document info.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIComponents defines OpenAPI reusable components.
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPIOperation defines OpenAPI operation of a path.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter defines OpenAPI operation parameter.
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody defines OpenAPI operation request body.
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse defines OpenAPI operation response.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType defines OpenAPI media type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema defines OpenAPI (JSON Schema) schema object.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	Enum                 []any                     `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64                  `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64                  `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AllOf                []*OpenAPISchema          `json:"allOf,omitempty"`
+}
+
+// JSON returns the OpenAPI document in JSON format.
+func (d *OpenAPIDocument) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML returns the OpenAPI document in YAML format.
+func (d *OpenAPIDocument) YAML() ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is valid YAML, decode it into node to keep the keys order then reset the flow style.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	var resetStyle func(n *yaml.Node)
+	resetStyle = func(n *yaml.Node) {
+		n.Style = 0
+		for _, child := range n.Content {
+			resetStyle(child)
+		}
+	}
+	resetStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+// OpenAPI generates OpenAPI 3.1 document from the routes of the default HTTP(s) server.
+func (app *App) OpenAPI() *OpenAPIDocument {
+	return app.OpenAPIOn(HTTP_SERVER_DEFAULT)
+}
+
+// OpenAPIOn generates OpenAPI 3.1 document from the routes of the named HTTP(s) server.
+// The request & response schema are reflected from the payload & result type of the handler chain,
+// using the `json`, `param`, `query`, `header` and `validate` struct tags.
+func (app *App) OpenAPIOn(name string) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI:    "3.1.0",
+		Info:       OpenAPIInfo{Title: app.Config.AppName, Version: app.Config.AppVersion},
+		Paths:      make(map[string]map[string]*OpenAPIOperation),
+		Components: OpenAPIComponents{Schemas: make(map[string]*OpenAPISchema)},
+	}
+	server := app.httpServerByName(name)
+	if server == nil {
+		return doc
+	}
+
+	g := &openAPIGenerator{schemas: doc.Components.Schemas, names: make(map[reflect.Type]string)}
+	envelope := g.schema(reflect.TypeFor[ApiResponseDefault]())
+
+	// Error response is written by the API response mode of the server, the problem details
+	// or `ApiResponseDefault` envelope.
+	errorMediaType, errorSchema, problem := "application/json", envelope, false
+	switch server.iApiResponse.(type) {
+	case ApiResponseProblemInterface, *ApiResponseProblemInterface:
+		errorMediaType, errorSchema, problem = HTTP_MIME_PROBLEM_JSON, g.schema(reflect.TypeFor[ApiResponseProblem]()), true
+	}
+	for _, route := range server.routes {
+		path, pathParams := openAPIPath(route.path)
+		operation := &OpenAPIOperation{
+			OperationID: openAPIOperationID(route.method, path),
+			Responses:   make(map[string]*OpenAPIResponse),
+		}
+		if tag := openAPITag(path); tag != "" {
+			operation.Tags = []string{tag}
+		}
+
+		// Request payload & result type.
+		var (
+			payloadType, resultType reflect.Type
+			status                  = int(HttpStatusOK)
+		)
+		if schema, ok := route.handler.(httpHandlerChainSchema); ok {
+			payloadType, resultType = schema.payloadType(), schema.resultType()
+			status = int(schema.successStatus())
+		}
+
+		// Parameters & body.
+		declared := make(map[string]struct{})
+		if payloadType != nil {
+			params, body := g.payload(payloadType)
+			for _, param := range params {
+				if param.In == "path" {
+					param.Required = true
+				}
+				declared[param.In+":"+param.Name] = struct{}{}
+				operation.Parameters = append(operation.Parameters, param)
+			}
+			if body != nil && route.method != http.MethodGet && route.method != http.MethodHead {
+				operation.RequestBody = &OpenAPIRequestBody{
+					Required: len(body.Required) > 0,
+					Content:  map[string]*OpenAPIMediaType{"application/json": {Schema: body}},
+				}
+			}
+		}
+		for _, param := range pathParams {
+			if _, ok := declared["path:"+param]; ok {
+				continue
+			}
+			operation.Parameters = append(operation.Parameters, &OpenAPIParameter{
+				Name: param, In: "path", Required: true, Schema: &OpenAPISchema{Type: "string"},
+			})
+		}
+
+		// Success response is wrapped by `ApiResponseDefault` envelope.
+		success := envelope
+		if resultType != nil {
+			success = &OpenAPISchema{AllOf: []*OpenAPISchema{envelope, {
+				Type:       "object",
+				Properties: map[string]*OpenAPISchema{"data": g.schema(resultType)},
+			}}}
+		}
+		operation.Responses[strconv.Itoa(status)] = openAPIResponse(http.StatusText(status), "application/json", success)
+		if payloadType != nil {
+			validation := errorSchema
+			if !problem {
+				validation = &OpenAPISchema{AllOf: []*OpenAPISchema{envelope, {
+					Type:       "object",
+					Properties: map[string]*OpenAPISchema{"error": g.schema(reflect.TypeFor[ApiResponseErrorDetail]())},
+				}}}
+			}
+			operation.Responses["400"] = openAPIResponse(http.StatusText(http.StatusBadRequest), errorMediaType, validation)
+		}
+		operation.Responses["500"] = openAPIResponse(http.StatusText(http.StatusInternalServerError), errorMediaType, errorSchema)
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(route.method)] = operation
+	}
+	return doc
+}
+
+// writeOpenAPI writes OpenAPI document of the default HTTP(s) server into given file,
+// the document format is YAML when the file extension is `.yaml` or `.yml`, otherwise JSON.
+func (app *App) writeOpenAPI(file string) error {
+	doc := app.OpenAPI()
+	data, err := doc.JSON()
+	if ext := strings.ToLower(filepath.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		data, err = doc.YAML()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write OpenAPI document %s: %w", file, err)
+	}
+	return nil
+}
+
+// setHttpOpenAPIRoute mounts OpenAPI document into the default HTTP(s) server.
+func (app *App) setHttpOpenAPIRoute() {
+	if ValidationIsEmpty(app.Config.HTTPOpenAPIPath) {
+		return
+	}
+	asYAML := strings.HasSuffix(app.Config.HTTPOpenAPIPath, ".yaml") || strings.HasSuffix(app.Config.HTTPOpenAPIPath, ".yml")
+	app.SetHttpRoutes(func(router *HttpRouter) {
+		router.Get(app.Config.HTTPOpenAPIPath, HttpHanlderChain(func(c HttpContext) error {
+			doc := app.OpenAPI()
+			if asYAML {
+				data, err := doc.YAML()
+				if err != nil {
+					return c.Api().Error(err).Response()
+				}
+				return c.Blob(http.StatusOK, "application/yaml", data)
+			}
+			data, err := doc.JSON()
+			if err != nil {
+				return c.Api().Error(err).Response()
+			}
+			return c.JSONBlob(http.StatusOK, data)
+		}))
+	})
+}
+
+func openAPIResponse(description, mediaType string, schema *OpenAPISchema) *OpenAPIResponse {
+	return &OpenAPIResponse{
+		Description: description,
+		Content:     map[string]*OpenAPIMediaType{mediaType: {Schema: schema}},
+	}
+}
+
+// openAPIPath converts echo path into OpenAPI path template and returns the path parameters.
+func openAPIPath(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		case segment == "*":
+			params = append(params, "*")
+			segments[i] = "{*}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// openAPIOperationID creates operation ID from method & path, e.g. `GET /users/{id}` is `getUsersId`.
+func openAPIOperationID(method, path string) string {
+	return strings.ToLower(method) + StringToCammelCase(StringToSnakeCase(path))
+}
+
+// openAPITag returns first static segment of the path as the operation tag.
+func openAPITag(path string) string {
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			return segment
+		}
+	}
+	return ""
+}
+
+type openAPIGenerator struct {
+	schemas map[string]*OpenAPISchema
+	names   map[reflect.Type]string
+}
+
+// payload reflects request payload type into parameters & body schema.
+func (g *openAPIGenerator) payload(t reflect.Type) (params []*OpenAPIParameter, body *OpenAPISchema) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	body = &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	g.fields(t, func(field reflect.StructField, schema *OpenAPISchema, required bool) {
+		for _, in := range []string{"param", "query", "header"} {
+			name := strings.Split(field.Tag.Get(in), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			location := in
+			if in == "param" {
+				location = "path"
+			}
+			params = append(params, &OpenAPIParameter{Name: name, In: location, Required: required, Schema: schema})
+		}
+		if name, ok := openAPIJSONName(field); ok {
+			body.Properties[name] = schema
+			if required {
+				body.Required = append(body.Required, name)
+			}
+		}
+	})
+	if len(body.Properties) == 0 {
+		body = nil
+	}
+	return
+}
+
+// fields iterates exported struct fields including the embedded struct.
+func (g *openAPIGenerator) fields(t reflect.Type, fn func(field reflect.StructField, schema *OpenAPISchema, required bool)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, fn)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		schema := g.schema(field.Type)
+		required := openAPIValidate(schema, field.Type, field.Tag.Get("validate"))
+		fn(field, schema, required)
+	}
+}
+
+// openAPIJSONName returns the JSON name of the field, the field that is only bound from
+// path, query or header is not part of the JSON body.
+func openAPIJSONName(field reflect.StructField) (string, bool) {
+	tag, hasTag := field.Tag.Lookup("json")
+	name := strings.Split(tag, ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if !hasTag {
+		for _, in := range []string{"param", "query", "header", "form"} {
+			if _, ok := field.Tag.Lookup(in); ok {
+				return "", false
+			}
+		}
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// schema reflects given type into schema, struct type is registered as component and referenced.
+func (g *openAPIGenerator) schema(t reflect.Type) *OpenAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case reflect.TypeFor[time.Duration]():
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := float64(0)
+		return &OpenAPISchema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.component(t)
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+// component registers struct type as component schema and returns the reference.
+func (g *openAPIGenerator) component(t reflect.Type) *OpenAPISchema {
+	if t.Name() == "" {
+		return g.object(t)
+	}
+	if name, ok := g.names[t]; ok {
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	}
+
+	// Prefix the package name on conflicted type name, then suffix the number until the name is unique.
+	name := openAPIComponentName(t)
+	if _, exists := g.schemas[name]; exists {
+		name = StringToCammelCase(filepath.Base(t.PkgPath())) + name
+	}
+	for base, i := name, 2; g.schemas[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.names[t] = name
+	g.schemas[name] = &OpenAPISchema{}
+	*g.schemas[name] = *g.object(t)
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+// openAPITypePackage matches the package path of the type name.
+var openAPITypePackage = regexp.MustCompile(`[\w./-]+\.`)
+
+// openAPIComponentName returns component name of the struct type, the generic type name includes
+// the type arguments without the package path, e.g. `Page[pkg.User]` is `PageUser`.
+func openAPIComponentName(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return name
+	}
+	return name + StringToCammelCase(openAPITypePackage.ReplaceAllString(args, ""))
+}
+
+// object reflects struct type into object schema.
+func (g *openAPIGenerator) object(t reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	g.fields(t, func(field reflect.StructField, s *OpenAPISchema, required bool) {
+		name, ok := openAPIJSONName(field)
+		if !ok {
+			return
+		}
+		schema.Properties[name] = s
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	})
+	return schema
+}
+
+// openAPIRequired returns true if the `validate` tag has exact `required` rule of the field,
+// the conditional rules like `required_if` and the rule of the element after `dive` are not.
+func openAPIRequired(tag string) bool {
+	for rule := range strings.SplitSeq(tag, ",") {
+		switch rule {
+		case "required":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
+
+// openAPIValidate applies `validate` tag rules into the schema and returns true if the field is required.
+func openAPIValidate(schema *OpenAPISchema, t reflect.Type, tag string) (required bool) {
+	if tag == "" || schema.Ref != "" {
+		return openAPIRequired(tag)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	target := schema
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if strings.Contains(name, "|") {
+			continue
+		}
+		kind := t.Kind()
+		if target != schema && t.Kind() != reflect.String {
+			elem := t.Elem()
+			for elem.Kind() == reflect.Pointer {
+				elem = elem.Elem()
+			}
+			kind = elem.Kind()
+		}
+
+		switch name {
+		case "required":
+			// The rule after `dive` is the element rule.
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			if schema.Items == nil {
+				return
+			}
+			target = schema.Items
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			openAPILimit(target, kind, name, n)
+		case "oneof":
+			for value := range strings.FieldsSeq(param) {
+				if n, err := strconv.ParseFloat(value, 64); err == nil && target.Type != "string" {
+					target.Enum = append(target.Enum, n)
+					continue
+				}
+				target.Enum = append(target.Enum, value)
+			}
+		default:
+			format, pattern := openAPIFormat(name)
+			if format == "" && pattern == "" {
+				continue
+			}
+			// Format rule of slice is applied into the items.
+			s := target
+			if s.Type == "array" && s.Items != nil {
+				s = s.Items
+			}
+			s.Format, s.Pattern = format, pattern
+		}
+	}
+	return
+}
+
+// openAPILimit applies numeric, length or items limit based on the kind.
+func openAPILimit(schema *OpenAPISchema, kind reflect.Kind, rule string, n float64) {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		size := int(n)
+		minP, maxP := &schema.MinLength, &schema.MaxLength
+		if kind != reflect.String {
+			minP, maxP = &schema.MinItems, &schema.MaxItems
+		}
+		switch rule {
+		case "min", "gte":
+			*minP = &size
+		case "gt":
+			size++
+			*minP = &size
+		case "max", "lte":
+			*maxP = &size
+		case "lt":
+			size--
+			*maxP = &size
+		case "len":
+			*minP, *maxP = &size, &size
+		}
+	default:
+		switch rule {
+		case "min", "gte":
+			schema.Minimum = &n
+		case "max", "lte":
+			schema.Maximum = &n
+		case "gt":
+			schema.ExclusiveMinimum = &n
+		case "lt":
+			schema.ExclusiveMaximum = &n
+		case "len":
+			schema.Minimum, schema.Maximum = &n, &n
+		}
+	}
+}
+
+// openAPIFormat maps validation tag into schema format or pattern.
+func openAPIFormat(tag string) (format, pattern string) {
+	switch tag {
+	case "email":
+		return "email", ""
+	case "url", "uri", "http_url":
+		return "uri", ""
+	case "uuid", "uuid3", "uuid4", "uuid5", HTTP_VALIDATION_UUID7:
+		return "uuid", ""
+	case "ipv4", "ip4_addr":
+		return "ipv4", ""
+	case "ipv6", "ip6_addr":
+		return "ipv6", ""
+	case "ip", "ip_addr":
+		return "ip", ""
+	case "cidr", "cidrv4", "cidrv6":
+		return "cidr", ""
+	case "ip_or_cidr":
+		return "ip-or-cidr", ""
+	case "hostname", "hostname_rfc1123":
+		return "hostname", ""
+	case "datetime":
+		return "date-time", ""
+	case "alpha":
+		return "", "^[a-zA-Z]+$"
+	case "alphanum":
+		return "", "^[a-zA-Z0-9]+$"
+	case "numeric":
+		return "", "^[-+]?[0-9]+(?:\\.[0-9]+)?$"
+	case "number":
+		return "", "^[0-9]+$"
+	case "ulid":
+		return "", "^[0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{26}$"
+	case HTTP_VALIDATION_ID_PHONE:
+		return "", httpValidationIdPhoneRegex.String()
+	case HTTP_VALIDATION_NIK:
+		return "", httpValidationNikRegex.String()
+	case HTTP_VALIDATION_SLUG:
+		return "", httpValidationSlugRegex.String()
+	case HTTP_VALIDATION_STRONG_PASSWORD:
+		return "password", ""
+	case "e164":
+		return "", "^\\+[1-9]?[0-9]{7,14}$"
+	}
+	return "", ""
+}
@@ -0,0 +1,162 @@
+package qore
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type openAPIAddressTest struct {
+	City string `json:"city" validate:"required,min=2"`
+}
+
+type openAPIPayloadTest struct {
+	ID      string             `param:"id" validate:"required,uuid"`
+	Page    int                `query:"page" validate:"gte=1"`
+	Name    string             `json:"name" validate:"required,max=50"`
+	Role    string             `json:"role" validate:"oneof=admin user"`
+	Tags    []string           `json:"tags" validate:"max=5,dive,alphanum"`
+	Address openAPIAddressTest `json:"address"`
+	BornAt  time.Time          `json:"born_at"`
+}
+
+func (p *openAPIPayloadTest) Validate() error { return nil }
+
+type openAPIResultTest struct {
+	ID string `json:"id"`
+}
+
+func TestOpenAPIOk(t *testing.T) {
+	app := &App{Config: defaultConfig, httpServers: make(map[string]*httpServer)}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	if err := app.AddHttpServer(HTTP_SERVER_DEFAULT, HttpServerConfig{Port: 3100}); err != nil {
+		t.Fatal(err)
+	}
+	app.SetHttpRoutes(func(router *HttpRouter) {
+		router.Put("users/:id", HttpHanlderChainWithPayloadAndResult(
+			func(c HttpContext, p *openAPIPayloadTest) (*openAPIResultTest, error) { return nil, nil },
+		).WithStatus(HttpStatusCreated))
+	})
+
+	doc := app.OpenAPI()
+	operation := doc.Paths["/users/{id}"]["put"]
+	if operation == nil {
+		t.Fatalf("operation is not generated: %v", doc.Paths)
+	}
+	if operation.OperationID != "putUsersId" {
+		t.Errorf("unexpected operation id %s", operation.OperationID)
+	}
+	if len(operation.Parameters) != 2 || operation.Parameters[0].In != "path" || operation.Parameters[0].Schema.Format != "uuid" {
+		t.Errorf("unexpected parameters %+v", operation.Parameters)
+	}
+	if operation.Responses["201"] == nil || operation.Responses["400"] == nil {
+		t.Errorf("unexpected responses %v", operation.Responses)
+	}
+	if content := operation.Responses["500"].Content["application/json"]; content == nil || content.Schema.Ref != "#/components/schemas/ApiResponseDefault" {
+		t.Errorf("unexpected error response %+v", operation.Responses["500"].Content)
+	}
+
+	body := operation.RequestBody.Content["application/json"].Schema
+	if len(body.Required) != 1 || body.Required[0] != "name" {
+		t.Errorf("unexpected required %v", body.Required)
+	}
+	if *body.Properties["name"].MaxLength != 50 || len(body.Properties["role"].Enum) != 2 {
+		t.Errorf("unexpected validation rules %+v", body.Properties)
+	}
+	if tags := body.Properties["tags"]; *tags.MaxItems != 5 || tags.Items.Pattern == "" {
+		t.Errorf("unexpected slice rules %+v", tags)
+	}
+	if body.Properties["born_at"].Format != "date-time" {
+		t.Errorf("unexpected time format %+v", body.Properties["born_at"])
+	}
+	if _, ok := doc.Components.Schemas["openAPIAddressTest"]; !ok {
+		t.Errorf("struct component is not registered %v", doc.Components.Schemas)
+	}
+	if _, err := doc.YAML(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOpenAPIProblemOk(t *testing.T) {
+	app := &App{Config: defaultConfig, httpServers: make(map[string]*httpServer)}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	if err := app.AddHttpServer(HTTP_SERVER_DEFAULT, HttpServerConfig{Port: 3100}); err != nil {
+		t.Fatal(err)
+	}
+	app.SetApiResponseInterface(ApiResponseProblemInterface{})
+	app.SetHttpRoutes(func(router *HttpRouter) {
+		router.Put("users/:id", HttpHanlderChainWithPayloadAndResult(
+			func(c HttpContext, p *openAPIPayloadTest) (*openAPIResultTest, error) { return nil, nil },
+		))
+	})
+
+	// The error response is the problem details, the success response is still the envelope.
+	operation := app.OpenAPI().Paths["/users/{id}"]["put"]
+	if operation == nil {
+		t.Fatal("operation is not generated")
+	}
+	if _, ok := operation.Responses["200"].Content["application/json"]; !ok {
+		t.Errorf("unexpected success response %+v", operation.Responses["200"].Content)
+	}
+	for _, status := range []string{"400", "500"} {
+		content := operation.Responses[status].Content[HTTP_MIME_PROBLEM_JSON]
+		if content == nil || content.Schema.Ref != "#/components/schemas/ApiResponseProblem" || len(operation.Responses[status].Content) != 1 {
+			t.Errorf("%s: unexpected error response %+v", status, operation.Responses[status].Content)
+		}
+	}
+}
+
+type openAPIPageTest[T any] struct {
+	Items []T `json:"items"`
+}
+
+type openAPIItemTest struct {
+	Name     string    `json:"name" validate:"required_with=Code"`
+	Code     string    `json:"code" validate:"required_if=Name x,max=10"`
+	Optional string    `json:"optional" validate:"required_without=Code"`
+	Price    int       `json:"price" validate:"required,gte=0"`
+	Labels   []string  `json:"labels" validate:"dive,required"`
+	Aliases  []*string `json:"aliases" validate:"dive,min=1"`
+}
+
+func TestOpenAPIComponentOk(t *testing.T) {
+	g := &openAPIGenerator{schemas: make(map[string]*OpenAPISchema), names: make(map[reflect.Type]string)}
+	refs := []*OpenAPISchema{
+		g.schema(reflect.TypeFor[openAPIPageTest[openAPIResultTest]]()),
+		g.schema(reflect.TypeFor[openAPIPageTest[openAPIAddressTest]]()),
+		g.schema(reflect.TypeFor[openAPIPageTest[openAPIItemTest]]()),
+		g.schema(reflect.TypeFor[openAPIPageTest[*openAPIItemTest]]()),
+	}
+	names := []string{
+		"openAPIPageTestOpenAPIResultTest",
+		"openAPIPageTestOpenAPIAddressTest",
+		"openAPIPageTestOpenAPIItemTest",
+		"QoreopenAPIPageTestOpenAPIItemTest",
+	}
+	for i, ref := range refs {
+		if want := "#/components/schemas/" + names[i]; ref.Ref != want {
+			t.Errorf("expected reference %s, got %s", want, ref.Ref)
+		}
+	}
+	if len(g.schemas) != 7 {
+		t.Errorf("expected 7 components, got %d", len(g.schemas))
+	}
+
+	// The same type name is suffixed by the number when the package prefixed name is conflicted too.
+	g.schemas["QoreopenAPIResultTest"] = &OpenAPISchema{}
+	g.schemas["openAPIResultTest"] = &OpenAPISchema{}
+	g.names = make(map[reflect.Type]string)
+	if ref := g.schema(reflect.TypeFor[openAPIResultTest]()); ref.Ref != "#/components/schemas/QoreopenAPIResultTest2" {
+		t.Errorf("unexpected reference %s", ref.Ref)
+	}
+
+	item := g.schemas["openAPIItemTest"]
+	if item == nil || len(item.Required) != 1 || item.Required[0] != "price" {
+		t.Errorf("expected only price is required, got %+v", item)
+	}
+	// The element rule of the pointer element is applied by the element kind.
+	if aliases := item.Properties["aliases"].Items; aliases == nil || aliases.MinLength == nil || *aliases.MinLength != 1 ||
+		aliases.Minimum != nil {
+		t.Errorf("unexpected pointer element rules %+v", aliases)
+	}
+}
@@ -2,11 +2,17 @@
 package qore
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
-	"sort"
+	"sync"
+	"sync/atomic"
 	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // App is the Qore main application.
@@ -17,14 +23,69 @@ type App struct {
 	// Unexported utility.
 	logger *logger
 
-	// Unexported application server.
-	httpServer *httpServer
+	// Unexported application server, `httpServer` is the default HTTP(s) server.
+	httpServer  *httpServer
+	httpServers map[string]*httpServer
+
+	// Unexported app-wide validator settings, they are applied to validator of every HTTP(s) server
+	// including the server that is added or the validator that is set later.
+	httpValidatorSettings []httpValidatorSetting
+
+	// Unexported gRPC server.
+	grpcServer *grpcServer
+
+	// Unexported application servers with addresses that can used by supervisor.
+	servers []appServerEntry
+
+	// Unexported loaded module in the module-load order.
+	modules []Module
+
+	// Unexported number of the started modules in the module-load order.
+	modulesStarted int
+
+	// Unexported error of the aborted server startup, the process exits with code 1 once the supervisor returns.
+	startErr error
+
+	// Unexported lifecycle context that is canceled when the application is stopping.
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	// Unexported background worker runner.
+	workerRunner workerRunner
 
-	// Unexported application addresses that can used by supervisor.
-	addresses []string
+	// Unexported task scheduler.
+	scheduler     *Scheduler
+	schedulerOnce sync.Once
 
 	// Unexported dependency registry
 	dependencyRegistry []Dependency
+
+	// Unexported opened dependency in the opening order.
+	dependencyOpened []Dependency
+
+	// Unexported degraded dependency (failed to open) by name, it is reopened by the dependency monitor.
+	dependencyDegraded map[string]error
+
+	// Unexported lock of the opened & degraded dependency.
+	dependencyMu sync.RWMutex
+
+	// Unexported pending open calls, the abandoned call that succeeds late is closed.
+	dependencyLate sync.WaitGroup
+
+	// Unexported background dependency health monitor.
+	dependencyMonitor dependencyMonitor
+
+	// Unexported OpenTelemetry tracer provider.
+	tracerProvider *sdktrace.TracerProvider
+
+	// Unexported Prometheus metrics registry.
+	metricsRegistry *prometheus.Registry
+
+	// Unexported domain error registry.
+	errorRegistry errorRegistry
+
+	// Unexported readiness flag, true when the application server is started.
+	ready atomic.Bool
 }
 
 // Logger instance that associated with the app.
@@ -34,39 +95,144 @@ func (a *App) Logger() *logger {
 
 // SetHttpMiddleware will set given middleware(s) to the global HTTP(S) middleware.
 func (app *App) SetHttpMiddleware(middlewares ...HttpMiddleware) {
-	if app.httpServer == nil {
+	app.SetHttpMiddlewareOn(HTTP_SERVER_DEFAULT, middlewares...)
+}
+
+// SetHttpMiddlewareOn will set given middleware(s) to the global middleware of the named HTTP(S) server.
+func (app *App) SetHttpMiddlewareOn(name string, middlewares ...HttpMiddleware) {
+	server := app.httpServerByName(name)
+	if server == nil {
 		return
 	}
-	app.httpServer.core.Use(httpMiddlewareWrappers(app.httpServer, app.logger, middlewares...)...)
+	server.core.Use(httpMiddlewareWrappers(server, app.logger, middlewares...)...)
 }
 
 // SetHttpRoutes creates HTTP routers object that can be used for registering HTTP route.
 func (app *App) SetHttpRoutes(fn func(router *HttpRouter)) {
-	if app.httpServer == nil {
+	app.SetHttpRoutesOn(HTTP_SERVER_DEFAULT, fn)
+}
+
+// SetHttpRoutesOn creates HTTP routers object of the named HTTP(S) server that can be used for registering HTTP route.
+func (app *App) SetHttpRoutesOn(name string, fn func(router *HttpRouter)) {
+	server := app.httpServerByName(name)
+	if server == nil {
 		return
 	}
-	router := &HttpRouter{server: app.httpServer, logger: app.logger}
+	router := &HttpRouter{server: server, logger: app.logger}
 	fn(router)
 }
 
 // SetHttpValidator will set custom request validator to the HTTP(S).
 func (app *App) SetHttpValidator(validator HttpValidator) {
-	if app.httpServer == nil {
+	app.SetHttpValidatorOn(HTTP_SERVER_DEFAULT, validator)
+}
+
+// SetHttpValidatorOn will set custom request validator to the named HTTP(S) server.
+func (app *App) SetHttpValidatorOn(name string, validator HttpValidator) {
+	server := app.httpServerByName(name)
+	if server == nil {
 		return
 	} else if validator == nil {
 		return
 	}
-	app.httpServer.validator = validator
+	if err := app.applyHttpValidatorSettings(validator); err != nil {
+		app.Logger().Error(fmt.Sprintf("http(s) server %s: %s", server.name, err.Error()))
+	}
+	server.validator = validator
+}
+
+// httpValidatorSetting defines app-wide validator setting.
+type httpValidatorSetting func(validator HttpValidator) error
+
+// setHttpValidatorSetting applies the setting into validator of every HTTP(S) server,
+// the setting is kept when it is succeed so it is applied into the server that is added later.
+func (app *App) setHttpValidatorSetting(setting httpValidatorSetting) error {
+	var errs []error
+	for _, server := range app.httpServers {
+		if err := setting(server.validator); err != nil {
+			errs = append(errs, fmt.Errorf("http(s) server %s: %w", server.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	app.httpValidatorSettings = append(app.httpValidatorSettings, setting)
+	return nil
+}
+
+// applyHttpValidatorSettings applies the kept app-wide settings into given validator.
+func (app *App) applyHttpValidatorSettings(validator HttpValidator) error {
+	var errs []error
+	for _, setting := range app.httpValidatorSettings {
+		if err := setting(validator); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterValidation registers custom validation rule by the tag into validator of every HTTP(S) server
+// that implements `HttpValidatorRegistrable`. Register the message of the tag by `RegisterValidationMessage`.
+//
+//	app.RegisterValidation("even", func(fl qore.HttpValidationFieldLevel) bool {
+//		return fl.Field().Int()%2 == 0
+//	})
+func (app *App) RegisterValidation(tag string, fn HttpValidationFunc) error {
+	return app.setHttpValidatorSetting(func(validator HttpValidator) error {
+		if v, ok := validator.(HttpValidatorRegistrable); ok {
+			return v.RegisterValidation(tag, fn)
+		}
+		return nil
+	})
+}
+
+// RegisterStructValidation registers struct level validation for the given struct types into validator
+// of every HTTP(S) server that implements `HttpValidatorRegistrable`.
+//
+//	app.RegisterStructValidation(func(sl qore.HttpValidationStructLevel) {
+//		req := sl.Current().Interface().(RequestDateRange)
+//		if req.End.Before(req.Start) {
+//			sl.ReportError(req.End, "end", "End", "gtfield", "start")
+//		}
+//	}, RequestDateRange{})
+func (app *App) RegisterStructValidation(fn HttpStructValidationFunc, types ...any) {
+	_ = app.setHttpValidatorSetting(func(validator HttpValidator) error {
+		if v, ok := validator.(HttpValidatorRegistrable); ok {
+			v.RegisterStructValidation(fn, types...)
+		}
+		return nil
+	})
+}
+
+// RegisterValidationMessage registers custom message of validation tag for the locale into
+// validator of every HTTP(S) server that implements `HttpValidatorLocalized`, the locale is added
+// when it is not registered yet. `{0}` is replaced by the field name and `{1}` is replaced by the tag parameter.
+//
+//	app.RegisterValidationMessage("id", "required", "{0} wajib diisi")
+//	app.RegisterValidationMessage("fr", "required", "{0} est obligatoire")
+func (app *App) RegisterValidationMessage(locale, tag, message string) error {
+	return app.setHttpValidatorSetting(func(validator HttpValidator) error {
+		if v, ok := validator.(HttpValidatorLocalized); ok {
+			return v.RegisterMessage(locale, tag, message)
+		}
+		return nil
+	})
 }
 
 // SetApiResponseInterface will set custom HTTP(s) API response wrapper.
 func (app *App) SetApiResponseInterface(iApiResponse ApiResponseInterface) {
-	if app.httpServer == nil {
+	app.SetApiResponseInterfaceOn(HTTP_SERVER_DEFAULT, iApiResponse)
+}
+
+// SetApiResponseInterfaceOn will set custom HTTP(s) API response wrapper to the named HTTP(S) server.
+func (app *App) SetApiResponseInterfaceOn(name string, iApiResponse ApiResponseInterface) {
+	server := app.httpServerByName(name)
+	if server == nil {
 		return
 	} else if iApiResponse == nil {
 		return
 	}
-	app.httpServer.iApiResponse = iApiResponse
+	server.iApiResponse = iApiResponse
 }
 
 // LoadModule will loaded all used module(s).
@@ -88,8 +254,16 @@ func (app *App) LoadModule(loader ModuleLoader) {
 	// Scanning modules.
 	dependencyMap := make(map[reflect.Type]Dependency)
 	for _, module := range modules {
+		if module == nil {
+			continue
+		}
+		app.modules = append(app.modules, module)
+
 		// Execute all `qore#Module` interface that implemented by module.
 		module.HttpRoutes(app)
+		if m, ok := module.(ModuleGrpc); ok {
+			m.GrpcServices(app)
+		}
 
 		moduleVal := reflect.ValueOf(module)
 		moduleType := moduleVal.Type()
@@ -174,6 +348,14 @@ func (app *App) LoadModule(loader ModuleLoader) {
 // Supervisor is process manager that handle application graceful lifecycle.
 // You can create supervisor by yourself using that implement Supervisor interface.
 //
+// When the `QORE_OPENAPI_EXPORT` env is set, the OpenAPI document is written into the file
+// of the env value and the application is not started, it is used by `qore openapi` command.
+//
+// Dependency that failed to open is handled by its `DependencyPolicy`, when the startup is aborted
+// the already opened dependencies are closed in reverse order and the process exits with code 1.
+// When `ModuleStarter.OnStart` of a module returns error, the supervisor stops the application without waiting
+// the signal, then the dependencies are closed and the process exits with code 1.
+//
 // Using default supervisor (SupervisorNon)
 //
 //	app.Start()
@@ -207,36 +389,44 @@ func (app *App) Start(supervisor ...Supervisor) {
 		}
 	}
 
-	// Open dependency.
-	if len(app.dependencyRegistry) > 0 {
-		sort.Slice(app.dependencyRegistry, func(i, j int) bool {
-			return app.dependencyRegistry[i].Priority() < app.dependencyRegistry[j].Priority()
-		})
-		for _, dependency := range app.dependencyRegistry {
-			if err := dependency.Open(); err != nil {
-				err = fmt.Errorf("dependency %s failed to open: %w", dependency.Name(), err)
-				app.Logger().Error(err.Error())
-				continue
-			}
-			app.Logger().Debug(fmt.Sprintf("dependency %s has been opened successfully", dependency.Name()))
+	// Export OpenAPI document only.
+	if file, ok := os.LookupEnv(OPENAPI_EXPORT_KEY); ok && !ValidationIsEmpty(file) {
+		if err := app.writeOpenAPI(file); err != nil {
+			app.Logger().Error(err.Error())
+			os.Exit(1)
 		}
+		app.Logger().Info(fmt.Sprintf("OpenAPI document is written into %s", file))
+		return
 	}
 
+	// Open dependency, abort the startup on failure.
+	if err := app.openDependencies(); err != nil {
+		app.abortStartup(err)
+		return
+	}
+	app.startDependencyMonitor()
+
 	// Run the application inside supervisor.
 	spv.Run(app)
 
 	// Close dependency.
-	if len(app.dependencyRegistry) > 0 {
-		sort.Slice(app.dependencyRegistry, func(i, j int) bool {
-			return app.dependencyRegistry[i].Priority() > app.dependencyRegistry[j].Priority()
-		})
-		for _, dependency := range app.dependencyRegistry {
-			if err := dependency.Close(); err != nil {
-				err = fmt.Errorf("dependency %s failed to close: %w", dependency.Name(), err)
-				app.Logger().Error(err.Error())
-				continue
-			}
-			app.Logger().Debug(fmt.Sprintf("dependency %s has been closed successfully", dependency.Name()))
-		}
+	app.stopDependencyMonitor()
+	app.closeDependencies()
+
+	// Flush the remaining spans.
+	app.shutdownTracing()
+
+	// The supervisor has stopped the aborted startup.
+	if app.startErr != nil {
+		os.Exit(1)
 	}
 }
+
+// abortStartup closes the opened dependencies then exits the process with code 1.
+func (app *App) abortStartup(err error) {
+	app.Logger().Error(fmt.Sprintf("application startup aborted: %s", err.Error()))
+	app.stopDependencyMonitor()
+	app.closeDependencies()
+	app.shutdownTracing()
+	os.Exit(1)
+}
@@ -0,0 +1,212 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSchedulerTaskInvalid    = errors.New("invalid scheduled task")
+	ErrSchedulerTaskDuplicated = errors.New("duplicated scheduled task name")
+)
+
+// ScheduleFunc defines a scheduled task function.
+// The context carries the trace ID (see `ContextGetTraceID`) and is canceled when the application is stopping.
+type ScheduleFunc func(ctx context.Context) error
+
+// ScheduleConfig defines the config for scheduled task.
+type ScheduleConfig struct {
+	// Location is the timezone of the cron expression.
+	// Optional. Default value time.Local.
+	Location *time.Location
+	// AllowOverlap allows the task to be run while the previous run is still running.
+	// Optional. Default value false.
+	AllowOverlap bool
+	// MissedRun defines the policy of the run that is missed due to overlap or late wake-up.
+	// Optional. Default value SCHEDULE_MISSED_SKIP.
+	MissedRun ScheduleMissedPolicy
+	// Timeout limits duration of every run, zero means no timeout.
+	// Optional. Default value 0.
+	Timeout time.Duration
+}
+
+// Scheduler runs tasks based on cron expression or fixed interval.
+// Every task is run by a background worker, so it is stopped when the application is stopping.
+type Scheduler struct {
+	app   *App
+	mu    sync.Mutex
+	names map[string]struct{}
+}
+
+type scheduledTask struct {
+	name     string
+	schedule schedule
+	fn       ScheduleFunc
+	config   ScheduleConfig
+
+	mu      sync.Mutex
+	running int // number of the running runs, more than one when AllowOverlap is set
+	pending bool
+	wg      sync.WaitGroup
+}
+
+// Scheduler returns the application task scheduler.
+//
+//	app.Scheduler().Cron("report", "0 7 * * MON-FRI", func(ctx context.Context) error {
+//		return report.Send(ctx)
+//	}, qore.ScheduleConfig{Location: jakarta})
+func (app *App) Scheduler() *Scheduler {
+	app.schedulerOnce.Do(func() {
+		app.scheduler = &Scheduler{app: app, names: make(map[string]struct{})}
+	})
+	return app.scheduler
+}
+
+// Cron registers task that is run based on standard 5-field or 6-field (with second) cron expression.
+// Macros like `@hourly`, `@daily`, `@weekly`, `@monthly` and `@yearly` are supported.
+// The expression is matched by the wall clock of the location, the time that does not exist when the DST starts
+// is not run, and the hour that is repeated when the DST ends is skipped, so every wall clock time is run once.
+func (s *Scheduler) Cron(name, expr string, fn ScheduleFunc, config ...ScheduleConfig) error {
+	cfg := scheduleConfig(config...)
+	cron, err := parseCron(expr, cfg.Location)
+	if err != nil {
+		return err
+	}
+	return s.add(name, cron, fn, cfg)
+}
+
+// Every registers task that is run on the fixed interval.
+func (s *Scheduler) Every(name string, interval time.Duration, fn ScheduleFunc, config ...ScheduleConfig) error {
+	if interval < time.Second {
+		return fmt.Errorf("%w %s: interval must be at least 1 second", ErrSchedulerTaskInvalid, name)
+	}
+	return s.add(name, scheduleEvery{interval: interval}, fn, scheduleConfig(config...))
+}
+
+// scheduleConfig returns given config with the default value.
+func scheduleConfig(config ...ScheduleConfig) ScheduleConfig {
+	var cfg ScheduleConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.MissedRun != SCHEDULE_MISSED_RUN_ONCE {
+		cfg.MissedRun = SCHEDULE_MISSED_SKIP
+	}
+	return cfg
+}
+
+func (s *Scheduler) add(name string, sch schedule, fn ScheduleFunc, config ScheduleConfig) error {
+	if ValidationIsEmpty(name) || fn == nil {
+		return fmt.Errorf("%w: name and function are required", ErrSchedulerTaskInvalid)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.names[name]; exists {
+		return fmt.Errorf("%w: %s", ErrSchedulerTaskDuplicated, name)
+	}
+	s.names[name] = struct{}{}
+
+	task := &scheduledTask{name: name, schedule: sch, fn: fn, config: config}
+	logger := s.app.Logger().Group("scheduler").With(slog.String("task", name))
+	s.app.AddWorker("scheduler."+name, func(ctx context.Context) error {
+		task.loop(ctx, logger)
+		return nil
+	}, WorkerConfig{RestartPolicy: WORKER_RESTART_ON_FAILURE})
+	return nil
+}
+
+// loop waits for every schedule time and runs the task until the context is canceled.
+func (t *scheduledTask) loop(ctx context.Context, logger *logger) {
+	defer t.wg.Wait()
+
+	next := t.schedule.next(time.Now())
+	for {
+		if next.IsZero() {
+			logger.Warn("scheduled task has no next run time")
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Late wake-up, the following schedule time has also passed.
+		now := time.Now()
+		if following := t.schedule.next(next); !following.After(now) && t.config.MissedRun == SCHEDULE_MISSED_SKIP {
+			logger.Warn(fmt.Sprintf("scheduled task run at %s was missed, skipped", next.Format(time.RFC3339)))
+			next = t.schedule.next(now)
+			continue
+		}
+		next = t.schedule.next(now)
+
+		// Overlap prevention.
+		t.mu.Lock()
+		if t.running > 0 && !t.config.AllowOverlap {
+			if t.config.MissedRun == SCHEDULE_MISSED_RUN_ONCE {
+				t.pending = true
+				logger.Debug("scheduled task is still running, the run is queued")
+			} else {
+				logger.Warn("scheduled task is still running, the run is skipped")
+			}
+			t.mu.Unlock()
+			continue
+		}
+		t.running++
+		t.mu.Unlock()
+
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			for {
+				t.run(ctx, logger)
+
+				t.mu.Lock()
+				if !t.pending || ctx.Err() != nil {
+					t.running--
+					t.pending = false
+					t.mu.Unlock()
+					return
+				}
+				t.pending = false
+				t.mu.Unlock()
+			}
+		}()
+	}
+}
+
+// run executes the task once with trace ID, timeout and panic recovery.
+func (t *scheduledTask) run(ctx context.Context, logger *logger) {
+	traceID, _ := StringAlphaNumRandom(32)
+	ctx = context.WithValue(ctx, CTX_TRACE_ID, traceID)
+	if t.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.config.Timeout)
+		defer cancel()
+	}
+	logger = logger.With("traceId", traceID)
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			stack := make([]byte, 4<<10)
+			stack = stack[:runtime.Stack(stack, false)]
+			logger.Error(fmt.Sprintf("scheduled task panic: %v", r), slog.String("stack", string(stack)))
+		}
+	}()
+	if err := t.fn(ctx); err != nil {
+		logger.Error(fmt.Sprintf("scheduled task failed: %s", err.Error()), slog.String("latency", time.Since(start).String()))
+		return
+	}
+	logger.Debug("scheduled task finished", slog.String("latency", time.Since(start).String()))
+}
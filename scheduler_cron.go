@@ -0,0 +1,228 @@
+package qore
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrSchedulerCronInvalid = errors.New("invalid cron expression")
+
+// schedule defines the next activation time of a scheduled task.
+type schedule interface {
+	next(t time.Time) time.Time
+}
+
+// scheduleEvery is fixed interval schedule.
+type scheduleEvery struct {
+	interval time.Duration
+}
+
+func (s scheduleEvery) next(t time.Time) time.Time {
+	return t.Add(s.interval).Truncate(time.Second)
+}
+
+// scheduleCron is cron expression schedule, every field is a bit set of the allowed values.
+type scheduleCron struct {
+	second, minute, hour, dom, month, dow uint64
+	location                              *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSecond = cronField{min: 0, max: 59}
+	cronMinute = cronField{min: 0, max: 59}
+	cronHour   = cronField{min: 0, max: 23}
+	cronDom    = cronField{min: 1, max: 31}
+	cronMonth  = cronField{min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	cronDow = cronField{min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+// cronStar is the flag bit to mark the field is unrestricted (`*` or `?`).
+const cronStar = uint64(1) << 63
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// parseCron parses standard 5-field (minute hour day-of-month month day-of-week)
+// or 6-field (with leading second) cron expression, also supports the macros like `@daily`.
+func parseCron(expr string, location *time.Location) (*scheduleCron, error) {
+	if location == nil {
+		location = time.Local
+	}
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w %q: expected 5 or 6 fields, got %d", ErrSchedulerCronInvalid, expr, len(fields))
+	}
+
+	s := &scheduleCron{location: location}
+	var err error
+	for i, target := range []*uint64{&s.second, &s.minute, &s.hour, &s.dom, &s.month, &s.dow} {
+		field := []cronField{cronSecond, cronMinute, cronHour, cronDom, cronMonth, cronDow}[i]
+		if *target, err = parseCronField(fields[i], field); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrSchedulerCronInvalid, expr, err)
+		}
+	}
+
+	// Sunday is allowed as 7 in the day of week.
+	if s.dow&(1<<7) > 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField parses comma separated cron field into bit set.
+func parseCronField(value string, field cronField) (bits uint64, err error) {
+	for part := range strings.SplitSeq(value, ",") {
+		// Step.
+		step := 1
+		rangePart := part
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		// Range.
+		start, end := field.min, field.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			if step == 1 {
+				bits |= cronStar
+			}
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			if start, err = field.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if end, err = field.value(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			if start, err = field.value(rangePart); err != nil {
+				return 0, err
+			}
+			end = start
+			if strings.Contains(part, "/") {
+				end = field.max
+			}
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return
+}
+
+// value parses single value or name of the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d]", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// dayMatches checks the day of month & day of week, when both of them are restricted
+// the day is matched if either of them is matched.
+func (s *scheduleCron) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) > 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) > 0
+	if s.dom&cronStar > 0 || s.dow&cronStar > 0 {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func (s *scheduleCron) next(t time.Time) time.Time {
+	origin := t.Location()
+	from := t.In(s.location).Add(-time.Duration(t.Nanosecond()))
+	t = from.Add(time.Second)
+	limit := t.AddDate(5, 0, 0)
+
+WRAP:
+	for t.Before(limit) {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			if t.Month() == time.January {
+				continue WRAP
+			}
+		}
+		for !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			if t.Day() == 1 {
+				continue WRAP
+			}
+		}
+		// The hour is advanced by duration, so the hour that does not exist when the DST starts is passed over.
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			day := t.Day()
+			t = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second).Add(time.Hour)
+			if t.Day() != day {
+				continue WRAP
+			}
+		}
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			if t.Minute() == 0 {
+				continue WRAP
+			}
+		}
+		for s.second&(1<<uint(t.Second())) == 0 {
+			t = t.Truncate(time.Second).Add(time.Second)
+			if t.Second() == 0 {
+				continue WRAP
+			}
+		}
+		// The wall clock is repeated when the DST ends, the repeated hour is skipped since its wall clock time
+		// has been run, e.g. hourly & every 15 minutes tasks are not run until the repeated hour is passed.
+		if wall := cronWallClock(t); !wall.After(cronWallClock(from)) {
+			t = t.Add(cronWallClock(from).Sub(wall) + time.Second)
+			continue WRAP
+		}
+		return t.In(origin)
+	}
+	return time.Time{}
+}
+
+// cronWallClock returns the wall clock of given time without the zone offset.
+func cronWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
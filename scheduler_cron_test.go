@@ -0,0 +1,81 @@
+package qore
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseCronNextOk(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	from := time.Date(2025, time.January, 31, 23, 59, 30, 0, time.UTC)
+	cases := []struct {
+		expr     string
+		location *time.Location
+		want     time.Time
+	}{
+		{"* * * * *", time.UTC, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * * *", time.UTC, time.Date(2025, time.January, 31, 23, 59, 45, 0, time.UTC)},
+		{"30 9 * * MON-FRI", time.UTC, time.Date(2025, time.February, 3, 9, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.UTC, time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * 7", time.UTC, time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)},
+		{"@monthly", time.UTC, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 7 * * *", jakarta, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := parseCron(c.expr, c.location)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.expr, err)
+		}
+		if got := s.next(from); !got.Equal(c.want) {
+			t.Errorf("%s: expected %s, got %s", c.expr, c.want, got)
+		}
+	}
+}
+
+func TestParseCronNextDSTOk(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// DST starts at 2025-03-09 02:00 EST, the clock jumps to 03:00 EDT.
+		{"0 * * * *", utc(time.March, 9, 6, 30), utc(time.March, 9, 7, 0)},
+		{"0 3 * * *", utc(time.March, 9, 5, 0), utc(time.March, 9, 7, 0)},
+		{"*/15 * * * *", utc(time.March, 9, 6, 45), utc(time.March, 9, 7, 0)},
+		{"30 2 * * *", utc(time.March, 9, 6, 0), utc(time.March, 10, 6, 30)},
+		// DST ends at 2025-11-02 02:00 EDT, the clock goes back to 01:00 EST, the repeated hour is skipped.
+		{"0 * * * *", utc(time.November, 2, 4, 30), utc(time.November, 2, 5, 0)},
+		{"0 * * * *", utc(time.November, 2, 5, 0), utc(time.November, 2, 7, 0)},
+		{"*/15 * * * *", utc(time.November, 2, 5, 30), utc(time.November, 2, 5, 45)},
+		{"*/15 * * * *", utc(time.November, 2, 5, 45), utc(time.November, 2, 7, 0)},
+		{"30 1 * * *", utc(time.November, 2, 5, 0), utc(time.November, 2, 5, 30)},
+		{"30 1 * * *", utc(time.November, 2, 5, 30), utc(time.November, 3, 6, 30)},
+		{"0 2 * * *", utc(time.November, 2, 4, 0), utc(time.November, 2, 7, 0)},
+	}
+	for _, c := range cases {
+		s, err := parseCron(c.expr, newYork)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.expr, err)
+		}
+		if got := s.next(c.from); !got.Equal(c.want) {
+			t.Errorf("%s from %s: expected %s, got %s", c.expr, c.from, c.want, got.UTC())
+		}
+	}
+}
+
+func TestParseCronErr(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * * * MON-XYZ", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := parseCron(expr, time.UTC); !errors.Is(err, ErrSchedulerCronInvalid) {
+			t.Errorf("%q: expected invalid cron error, got %v", expr, err)
+		}
+	}
+}
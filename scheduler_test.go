@@ -0,0 +1,140 @@
+package qore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scheduleTest is schedule that is defined by the function.
+type scheduleTest func(t time.Time) time.Time
+
+func (s scheduleTest) next(t time.Time) time.Time { return s(t) }
+
+func newScheduledTaskTest(sch schedule, fn ScheduleFunc, config ScheduleConfig) (task *scheduledTask, stop func()) {
+	task = &scheduledTask{name: "test", schedule: sch, fn: fn, config: scheduleConfig(config)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		task.loop(ctx, setupLogger(&Config{LogLevel: LOG_ERROR}))
+	}()
+	return task, func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestScheduleOverlapOk(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  ScheduleConfig
+		runs    int32
+		overlap bool
+	}{
+		{"skip", ScheduleConfig{}, 1, false},
+		{"run once", ScheduleConfig{MissedRun: SCHEDULE_MISSED_RUN_ONCE}, 2, false},
+		{"allow overlap", ScheduleConfig{AllowOverlap: true}, 3, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Run every 20ms until stopped, then every hour.
+			var stopped atomic.Bool
+			sch := scheduleTest(func(t time.Time) time.Time {
+				if stopped.Load() {
+					return t.Add(time.Hour)
+				}
+				return t.Add(20 * time.Millisecond)
+			})
+
+			var runs, running, concurrent atomic.Int32
+			release := make(chan struct{})
+			var releaseOnce sync.Once
+			task, stop := newScheduledTaskTest(sch, func(ctx context.Context) error {
+				runs.Add(1)
+				if n := running.Add(1); n > 1 {
+					concurrent.Store(n)
+				}
+				defer running.Add(-1)
+				<-release
+				return nil
+			}, c.config)
+			defer stop()
+			defer releaseOnce.Do(func() { close(release) })
+
+			time.Sleep(100 * time.Millisecond)
+			stopped.Store(true)
+			time.Sleep(60 * time.Millisecond)
+
+			// Every overlapping run is counted until it finishes.
+			task.mu.Lock()
+			if taskRunning := task.running; taskRunning != int(running.Load()) {
+				t.Errorf("expected %d running runs, got %d", running.Load(), taskRunning)
+			}
+			task.mu.Unlock()
+			releaseOnce.Do(func() { close(release) })
+			time.Sleep(60 * time.Millisecond)
+			task.mu.Lock()
+			if task.running != 0 {
+				t.Errorf("expected no running run, got %d", task.running)
+			}
+			task.mu.Unlock()
+
+			if c.overlap {
+				if concurrent.Load() == 0 {
+					t.Errorf("expected overlapping runs")
+				}
+				if got := runs.Load(); got < c.runs {
+					t.Errorf("expected at least %d runs, got %d", c.runs, got)
+				}
+				return
+			}
+			if concurrent.Load() > 0 {
+				t.Errorf("expected no overlapping run, got %d concurrent runs", concurrent.Load())
+			}
+			if got := runs.Load(); got != c.runs {
+				t.Errorf("expected %d runs, got %d", c.runs, got)
+			}
+		})
+	}
+}
+
+func TestScheduleMissedRunOk(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy ScheduleMissedPolicy
+		runs   int32
+	}{
+		{"skip", SCHEDULE_MISSED_SKIP, 0},
+		{"run once", SCHEDULE_MISSED_RUN_ONCE, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// The first run time is in the past & the following run time has also passed, like late wake-up.
+			var calls atomic.Int32
+			sch := scheduleTest(func(t time.Time) time.Time {
+				switch calls.Add(1) {
+				case 1:
+					return t.Add(-time.Second)
+				case 2:
+					return t.Add(100 * time.Millisecond)
+				}
+				return t.Add(time.Hour)
+			})
+
+			var runs atomic.Int32
+			_, stop := newScheduledTaskTest(sch, func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			}, ScheduleConfig{MissedRun: c.policy})
+			time.Sleep(50 * time.Millisecond)
+			stop()
+
+			if got := runs.Load(); got != c.runs {
+				t.Errorf("expected %d runs, got %d", c.runs, got)
+			}
+		})
+	}
+}
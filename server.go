@@ -0,0 +1,52 @@
+package qore
+
+import (
+	"fmt"
+	"net"
+)
+
+// appServer is the application server that is started & stopped by the supervisor.
+type appServer interface {
+	start(lfn func() (listener net.Listener, err error), logger *logger)
+	stop(logger *logger)
+}
+
+// appServerEntry defines registered application server and its listen address.
+// The order of entries is the order of listener that is handed by the supervisor.
+type appServerEntry struct {
+	name    string
+	address string
+	server  appServer
+}
+
+// addServer registers application server with given listen address.
+func (app *App) addServer(name, address string, server appServer) {
+	app.servers = append(app.servers, appServerEntry{name: name, address: address, server: server})
+}
+
+// serverAddresses returns listen address of all registered application server.
+func (app *App) serverAddresses() []string {
+	addresses := make([]string, 0, len(app.servers))
+	for _, entry := range app.servers {
+		addresses = append(addresses, entry.address)
+	}
+	return addresses
+}
+
+// listenTCP returns function that listen TCP on given address.
+func listenTCP(address string) func() (net.Listener, error) {
+	return func() (listener net.Listener, err error) {
+		// Resolve TCP.
+		addr, err := net.ResolveTCPAddr("tcp", address)
+		if err != nil {
+			return nil, fmt.Errorf("failed resolve listen %s: %w", address, err)
+		}
+
+		// Listen TCP.
+		listener, err = net.ListenTCP("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen TCP %s: %w", address, err)
+		}
+		return
+	}
+}
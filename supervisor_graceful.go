@@ -59,7 +59,7 @@ func (s *SupervisorGraceful) Run(app *App) {
 	// Run under overseer.
 	overseer.Run(overseer.Config{
 		Program:       s.program,
-		Addresses:     app.addresses,
+		Addresses:     app.serverAddresses(),
 		Debug:         !app.Config.AppProduction,
 		RestartSignal: s.GracefulRestartSignal,
 		Fetcher: &fetcher.File{
@@ -75,15 +75,15 @@ func (s *SupervisorGraceful) program(state overseer.State) {
 		s.HookStart()
 	}
 
-	// Start running the application server.
-	s.app.startServer(state.Listeners...)
+	// Start running the application server, the aborted startup is stopped without waiting the signal.
+	if err := s.app.startServer(state.Listeners...); err == nil {
+		// Signal notify.
+		ctx, stop := signal.NotifyContext(context.Background(), s.Signals...)
+		defer stop()
 
-	// Signal notify.
-	ctx, stop := signal.NotifyContext(context.Background(), s.Signals...)
-	defer stop()
-
-	// Terminate.
-	<-ctx.Done()
+		// Terminate.
+		<-ctx.Done()
+	}
 
 	// Execute hook function before stop if any.
 	if s.HookStop != nil {
@@ -91,5 +91,5 @@ func (s *SupervisorGraceful) program(state overseer.State) {
 	}
 
 	// Stop the running application server.
-	// Question: is needed to stop tcp listener while using overseer?
+	s.app.stopServer()
 }
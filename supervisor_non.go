@@ -41,15 +41,15 @@ func (s *SupervisorNon) Run(app *App) {
 		s.HookStart()
 	}
 
-	// Start running the application server.
-	app.startServer()
-
-	// Signal notify.
-	ctx, stop := signal.NotifyContext(context.Background(), s.Signals...)
-	defer stop()
-
-	// Terminate.
-	<-ctx.Done()
+	// Start running the application server, the aborted startup is stopped without waiting the signal.
+	if err := app.startServer(); err == nil {
+		// Signal notify.
+		ctx, stop := signal.NotifyContext(context.Background(), s.Signals...)
+		defer stop()
+
+		// Terminate.
+		<-ctx.Done()
+	}
 
 	// Execute hook function before stop if any.
 	if s.HookStop != nil {
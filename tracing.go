@@ -0,0 +1,121 @@
+package qore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer returns OpenTelemetry tracer of the global tracer provider that is set up by `New`.
+//
+//	ctx, span := qore.Tracer().Start(qore.ContextFromHttp(c), "user.create")
+//	defer span.End()
+func Tracer() trace.Tracer {
+	return otel.Tracer(TRACER_NAME)
+}
+
+// TraceInject writes W3C `traceparent` & `tracestate` of the context span into the carrier,
+// e.g. `propagation.HeaderCarrier(req.Header)` of the outgoing HTTP(s) request.
+func TraceInject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// TraceExtract reads W3C `traceparent` & `tracestate` from the carrier into the context.
+func TraceExtract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// TraceDependencyCall calls fn inside the span of the dependency operation, it can be used
+// by the dependency package to trace the call, e.g. database query.
+//
+//	err := qore.TraceDependencyCall(ctx, "postgres", "query", func(ctx context.Context) error {
+//		return db.QueryRowContext(ctx, query).Scan(&user)
+//	})
+func TraceDependencyCall(ctx context.Context, name, operation string, fn func(ctx context.Context) error) error {
+	ctx, span := Tracer().Start(ctx, fmt.Sprintf("dependency.%s %s", operation, name),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("qore.dependency.name", name),
+			attribute.String("qore.dependency.operation", operation),
+		),
+	)
+	defer span.End()
+
+	err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// setupTracing sets up the global OpenTelemetry tracer provider & W3C trace context propagator.
+// The SDK tracer provider is only set when `Config.TracingEndpoint` is set, so the span is exported
+// via OTLP/HTTP and sampled by `Config.TracingSampleRatio`. `Config.TracingSampleRatio` 0 samples
+// the root span out, the span of sampled parent is still sampled.
+//
+// Without the endpoint the global tracer provider is kept, it is the tracer provider that is set by the user
+// before `New` or the no-op provider that only propagates the incoming trace context.
+// The propagator is always set.
+func (app *App) setupTracing() error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if ValidationIsEmpty(app.Config.TracingEndpoint) {
+		return nil
+	}
+
+	endpoint, err := url.Parse(app.Config.TracingEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return fmt.Errorf("invalid tracing endpoint %s", app.Config.TracingEndpoint)
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = "/v1/traces"
+	}
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		return fmt.Errorf("failed to create tracing exporter: %w", err)
+	}
+
+	ratio := app.Config.TracingSampleRatio
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", app.Config.AppName),
+			attribute.String("service.version", app.Config.AppVersion),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithBatcher(exporter),
+	}
+
+	app.tracerProvider = sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(app.tracerProvider)
+	return nil
+}
+
+// shutdownTracing flushes the remaining spans & stops the tracer provider.
+func (app *App) shutdownTracing() {
+	if app.tracerProvider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.Config.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := app.tracerProvider.Shutdown(ctx); err != nil {
+		app.Logger().Error(fmt.Sprintf("failed to shutdown tracer provider: %s", err.Error()))
+	}
+}
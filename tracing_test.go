@@ -0,0 +1,194 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// testTraceCollector is in-process OTLP/HTTP collector that keeps the exported span names.
+type testTraceCollector struct {
+	mu    sync.Mutex
+	spans map[string]string
+}
+
+func (c *testTraceCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := new(collectortrace.ExportTraceServiceRequest)
+	if err := proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.spans[span.Name] = span.Status.GetCode().String()
+			}
+		}
+	}
+	c.mu.Unlock()
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestTracingExportOk(t *testing.T) {
+	collector := &testTraceCollector{spans: make(map[string]string)}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	app := &App{Config: &Config{AppName: "qore-test", TracingEndpoint: server.URL, TracingSampleRatio: 1, ShutdownTimeout: 5}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	if err := app.setupTracing(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, span := Tracer().Start(context.Background(), "GET /users")
+	_ = TraceDependencyCall(ctx, "postgres", "query", func(ctx context.Context) error {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			t.Error("expected dependency span in the context")
+		}
+		return nil
+	})
+	_ = TraceDependencyCall(ctx, "redis", "get", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	span.End()
+	app.shutdownTracing()
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	expected := map[string]string{
+		"GET /users":                "STATUS_CODE_UNSET",
+		"dependency.query postgres": "STATUS_CODE_UNSET",
+		"dependency.get redis":      "STATUS_CODE_ERROR",
+	}
+	for name, status := range expected {
+		got, ok := collector.spans[name]
+		if !ok {
+			t.Errorf("expected span %s is exported", name)
+			continue
+		}
+		if got != status {
+			t.Errorf("span %s expected status %s, got %s", name, status, got)
+		}
+	}
+}
+
+func TestTracingPropagationOk(t *testing.T) {
+	app := &App{Config: &Config{}}
+	if err := app.setupTracing(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.shutdownTracing()
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+	header.Set("tracestate", "vendor=value")
+
+	ctx := TraceExtract(context.Background(), propagation.HeaderCarrier(header))
+	ctx, span := Tracer().Start(ctx, "child")
+	defer span.End()
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace ID is inherited, got %s", got)
+	}
+
+	out := http.Header{}
+	TraceInject(ctx, propagation.HeaderCarrier(out))
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + span.SpanContext().SpanID().String() + "-01"
+	if got := out.Get("traceparent"); got != want {
+		t.Errorf("expected traceparent %s, got %s", want, got)
+	}
+	if got := out.Get("tracestate"); got != "vendor=value" {
+		t.Errorf("expected tracestate is propagated, got %s", got)
+	}
+}
+
+func TestTracingSampleRatioOk(t *testing.T) {
+	server := httptest.NewServer(&testTraceCollector{spans: make(map[string]string)})
+	defer server.Close()
+
+	app := &App{Config: &Config{TracingEndpoint: server.URL, TracingSampleRatio: 0, ShutdownTimeout: 1}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	if err := app.setupTracing(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.shutdownTracing()
+
+	_, span := Tracer().Start(context.Background(), "root")
+	span.End()
+	if span.SpanContext().IsSampled() {
+		t.Error("expected root span is not sampled by ratio 0")
+	}
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span = Tracer().Start(TraceExtract(context.Background(), propagation.HeaderCarrier(header)), "child")
+	span.End()
+	if !span.SpanContext().IsSampled() {
+		t.Error("expected span of sampled parent is sampled")
+	}
+}
+
+func TestTracingNoEndpointOk(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	app := &App{Config: &Config{TracingSampleRatio: 1}}
+	if err := app.setupTracing(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.shutdownTracing()
+
+	// The span is not recorded without exporter.
+	if otel.GetTracerProvider() != previous || app.tracerProvider != nil {
+		t.Error("expected tracer provider is kept without the endpoint")
+	}
+}
+
+func TestTracingUserProviderOk(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	defer func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	app := &App{Config: &Config{TracingSampleRatio: 1}}
+	if err := app.setupTracing(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.shutdownTracing()
+	if otel.GetTracerProvider() != provider || app.tracerProvider != nil {
+		t.Error("expected tracer provider of the user is kept")
+	}
+	if fields := otel.GetTextMapPropagator().Fields(); !slices.Contains(fields, "traceparent") ||
+		!slices.Contains(fields, "tracestate") || !slices.Contains(fields, "baggage") {
+		t.Errorf("expected W3C trace context & baggage propagator, got %v", fields)
+	}
+
+	// The endpoint enables qore tracing.
+	app = &App{Config: &Config{TracingEndpoint: "http://localhost:4318", TracingSampleRatio: 1, ShutdownTimeout: 1}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	if err := app.setupTracing(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.shutdownTracing()
+	if otel.GetTracerProvider() != app.tracerProvider {
+		t.Error("expected tracer provider of qore is set")
+	}
+}
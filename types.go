@@ -1,6 +1,7 @@
 package qore
 
 import (
+	"context"
 	"os"
 )
 
@@ -29,11 +30,51 @@ type Validatable interface {
 // LogLevel custom type for logging level.
 type LogLevel string
 
+// DependencyPolicy custom type for dependency open policy.
+type DependencyPolicy string
+
+// DependencyState custom type for dependency health state.
+type DependencyState string
+
+// WorkerRestartPolicy custom type for background worker restart policy.
+type WorkerRestartPolicy string
+
+// WorkerState custom type for background worker state.
+type WorkerState string
+
+// ScheduleMissedPolicy custom type for scheduled task missed-run policy.
+type ScheduleMissedPolicy string
+
 // Module is qore module interface.
 type Module interface {
 	HttpRoutes(app *App)
 }
 
+// ModuleGrpc is optional interface for `Module` that registers gRPC services.
+type ModuleGrpc interface {
+	GrpcServices(app *App)
+}
+
+// ModuleStarter is optional interface for `Module` that is executed in module-load order
+// before the application server start. The context is canceled when the application is stopping.
+// The startup is aborted at the first error and the already started modules are stopped.
+type ModuleStarter interface {
+	OnStart(ctx context.Context) error
+}
+
+// ModuleReadier is optional interface for `Module` that is executed in module-load order
+// after the application server started.
+type ModuleReadier interface {
+	OnReady()
+}
+
+// ModuleStopper is optional interface for `Module` that is executed in reverse module-load order
+// after the application server stopped, the module that never started is not stopped.
+// The context deadline is derived from `Config.ShutdownTimeout`.
+type ModuleStopper interface {
+	OnStop(ctx context.Context) error
+}
+
 // ModuleLoader is module loader interface.
 type ModuleLoader interface {
 	Load() []Module
@@ -47,3 +47,19 @@ func ValidationIsEmpty[T Validatable](s T) bool {
 		return v == nil
 	}
 }
+
+// ValidationIsTraceID checks is value of argument s was valid trace ID that is safe to be echoed
+// into the response header & log, it is at most `TRACE_ID_MAX_LENGTH` of alphanumeric, '-', '_' or '.' characters.
+func ValidationIsTraceID(s string) bool {
+	if len(s) == 0 || len(s) > TRACE_ID_MAX_LENGTH {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
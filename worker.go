@@ -0,0 +1,264 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// WorkerFunc defines a background worker function, it must return when the context is canceled.
+type WorkerFunc func(ctx context.Context) error
+
+// WorkerConfig defines the config for background worker.
+type WorkerConfig struct {
+	// RestartPolicy defines when the worker is restarted after it returns.
+	// Optional. Default value WORKER_RESTART_ON_FAILURE.
+	RestartPolicy WorkerRestartPolicy
+	// MaxRestarts limits the number of restart, zero means unlimited.
+	// Optional. Default value 0.
+	MaxRestarts int
+	// BackoffMin is the initial delay before restarting the worker, it is doubled on every restart.
+	// Optional. Default value 1 second.
+	BackoffMin time.Duration
+	// BackoffMax is the maximum delay before restarting the worker.
+	// Optional. Default value 1 minute.
+	BackoffMax time.Duration
+	// Jitter is random factor (0-1) that is added to the restart delay, zero disables the jitter.
+	// Optional. Default value 0.2 when the config is not given, out of range value is the default value.
+	Jitter float64
+}
+
+// WorkerStatus defines status of a background worker.
+type WorkerStatus struct {
+	Name      string      `json:"name" xml:"name"`
+	State     WorkerState `json:"state" xml:"state"`
+	Restarts  int         `json:"restarts" xml:"restarts"`
+	Failures  int         `json:"failures" xml:"failures"`
+	Panics    int         `json:"panics" xml:"panics"`
+	LastError string      `json:"lastError,omitempty" xml:"lastError,omitempty"`
+}
+
+var defaultWorkerConfig = WorkerConfig{
+	RestartPolicy: WORKER_RESTART_ON_FAILURE,
+	BackoffMin:    time.Second,
+	BackoffMax:    time.Minute,
+	Jitter:        0.2,
+}
+
+var errWorkerPanic = errors.New("worker panic")
+
+type worker struct {
+	name   string
+	fn     WorkerFunc
+	config WorkerConfig
+
+	mu     sync.RWMutex
+	status WorkerStatus
+}
+
+type workerRunner struct {
+	mu      sync.Mutex
+	workers []*worker
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// AddWorker registers background worker that is managed by the application lifecycle.
+// Worker is started after the module start hooks and canceled when the supervisor receives stop signal.
+// Worker that is added while the application is running is started immediately.
+//
+//	app.AddWorker("consumer", func(ctx context.Context) error {
+//		return consumer.Consume(ctx)
+//	}, qore.WorkerConfig{RestartPolicy: qore.WORKER_RESTART_ALWAYS})
+func (app *App) AddWorker(name string, fn WorkerFunc, config ...WorkerConfig) {
+	if ValidationIsEmpty(name) || fn == nil {
+		return
+	}
+
+	// Get config or default.
+	cfg := defaultWorkerConfig
+	if len(config) > 0 {
+		cfg = config[0]
+		if ValidationIsEmpty(string(cfg.RestartPolicy)) {
+			cfg.RestartPolicy = defaultWorkerConfig.RestartPolicy
+		}
+		if cfg.BackoffMin <= 0 {
+			cfg.BackoffMin = defaultWorkerConfig.BackoffMin
+		}
+		if cfg.BackoffMax < cfg.BackoffMin {
+			cfg.BackoffMax = max(defaultWorkerConfig.BackoffMax, cfg.BackoffMin)
+		}
+		if cfg.Jitter < 0 || cfg.Jitter > 1 {
+			cfg.Jitter = defaultWorkerConfig.Jitter
+		}
+	}
+
+	w := &worker{
+		name:   name,
+		fn:     fn,
+		config: cfg,
+		status: WorkerStatus{Name: name, State: WORKER_STATE_IDLE},
+	}
+	runner := &app.workerRunner
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	runner.workers = append(runner.workers, w)
+	if runner.ctx != nil && runner.ctx.Err() == nil {
+		runner.run(w, app.Logger().Group("worker"))
+	}
+}
+
+// WorkerStatuses returns status of all registered background worker.
+func (app *App) WorkerStatuses() []WorkerStatus {
+	runner := &app.workerRunner
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	statuses := make([]WorkerStatus, 0, len(runner.workers))
+	for _, w := range runner.workers {
+		w.mu.RLock()
+		statuses = append(statuses, w.status)
+		w.mu.RUnlock()
+	}
+	return statuses
+}
+
+// startWorkers starts all registered background worker.
+func (app *App) startWorkers(ctx context.Context) {
+	runner := &app.workerRunner
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	runner.ctx, runner.cancel = context.WithCancel(ctx)
+	logger := app.Logger().Group("worker")
+	for _, w := range runner.workers {
+		runner.run(w, logger)
+	}
+}
+
+// stopWorkers cancels all running background worker and wait until they return or the timeout exceeded.
+func (app *App) stopWorkers(logger *logger) {
+	runner := &app.workerRunner
+	runner.mu.Lock()
+	if runner.cancel == nil {
+		runner.mu.Unlock()
+		return
+	}
+	runner.cancel()
+	runner.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		runner.wg.Wait()
+		close(done)
+	}()
+	timeout := time.Duration(app.Config.ShutdownTimeout) * time.Second
+	select {
+	case <-done:
+		logger.Debug("background workers were stopped")
+	case <-time.After(timeout):
+		logger.Error(fmt.Sprintf("background workers did not stop after %s", timeout))
+	}
+}
+
+// run starts the worker goroutine, the caller must hold the runner lock.
+func (r *workerRunner) run(w *worker, logger *logger) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		w.loop(r.ctx, logger.With(slog.String("name", w.name)))
+	}()
+}
+
+func (w *worker) setState(state WorkerState, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.State = state
+	if err != nil {
+		w.status.Failures++
+		w.status.LastError = err.Error()
+		if errors.Is(err, errWorkerPanic) {
+			w.status.Panics++
+		}
+	}
+}
+
+// call executes the worker function with panic recovery.
+func (w *worker) call(ctx context.Context, logger *logger) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := make([]byte, 4<<10)
+			stack = stack[:runtime.Stack(stack, false)]
+			err = fmt.Errorf("%w: %v", errWorkerPanic, r)
+			logger.Error(err.Error(), slog.String("stack", string(stack)))
+		}
+	}()
+	return w.fn(ctx)
+}
+
+// loop runs the worker and restarts it based on the restart policy.
+func (w *worker) loop(ctx context.Context, logger *logger) {
+	backoff := w.config.BackoffMin
+	for {
+		w.setState(WORKER_STATE_RUNNING, nil)
+		logger.Debug("worker started")
+		started := time.Now()
+		err := w.call(ctx, logger)
+
+		// Orderly cancellation.
+		if ctx.Err() != nil {
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error(fmt.Sprintf("worker stopped with error: %s", err.Error()))
+			}
+			w.setState(WORKER_STATE_STOPPED, nil)
+			logger.Debug("worker stopped")
+			return
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("worker failed: %s", err.Error()))
+		}
+
+		// Check restart policy.
+		w.mu.RLock()
+		restarts := w.status.Restarts
+		w.mu.RUnlock()
+		switch {
+		case w.config.RestartPolicy == WORKER_RESTART_NEVER,
+			w.config.RestartPolicy == WORKER_RESTART_ON_FAILURE && err == nil:
+			state := WORKER_STATE_STOPPED
+			if err != nil {
+				state = WORKER_STATE_FAILED
+			}
+			w.setState(state, err)
+			logger.Debug("worker finished")
+			return
+		case w.config.MaxRestarts > 0 && restarts >= w.config.MaxRestarts:
+			w.setState(WORKER_STATE_FAILED, err)
+			logger.Error(fmt.Sprintf("worker reached max restarts %d", w.config.MaxRestarts))
+			return
+		}
+
+		// Backoff with jitter, reset when the worker has been running longer than the max backoff.
+		if time.Since(started) > w.config.BackoffMax {
+			backoff = w.config.BackoffMin
+		}
+		delay := backoff + time.Duration(rand.Float64()*w.config.Jitter*float64(backoff))
+		w.setState(WORKER_STATE_BACKOFF, err)
+		logger.Warn(fmt.Sprintf("worker restarting in %s", delay))
+		select {
+		case <-ctx.Done():
+			w.setState(WORKER_STATE_STOPPED, nil)
+			return
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, w.config.BackoffMax)
+
+		w.mu.Lock()
+		w.status.Restarts++
+		w.mu.Unlock()
+	}
+}
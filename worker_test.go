@@ -0,0 +1,140 @@
+package qore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newWorkerTest returns worker that fails the first `fails` calls, by error or by panic.
+func newWorkerTest(config WorkerConfig, fails int, panics bool, calls *int) *worker {
+	fn := func(ctx context.Context) error {
+		*calls++
+		if *calls > fails {
+			return nil
+		}
+		if panics {
+			panic("boom")
+		}
+		return errors.New("failed")
+	}
+	return &worker{name: "test", fn: fn, config: config, status: WorkerStatus{Name: "test", State: WORKER_STATE_IDLE}}
+}
+
+func workerTestConfig(policy WorkerRestartPolicy, maxRestarts int) WorkerConfig {
+	return WorkerConfig{
+		RestartPolicy: policy,
+		MaxRestarts:   maxRestarts,
+		BackoffMin:    time.Millisecond,
+		BackoffMax:    4 * time.Millisecond,
+	}
+}
+
+func TestWorkerRestartPolicyOk(t *testing.T) {
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	tests := []struct {
+		name     string
+		config   WorkerConfig
+		fails    int
+		panics   bool
+		calls    int
+		expected WorkerStatus
+	}{
+		{
+			name: "on failure restarts until success", config: workerTestConfig(WORKER_RESTART_ON_FAILURE, 0), fails: 3,
+			calls: 4, expected: WorkerStatus{State: WORKER_STATE_STOPPED, Restarts: 3, Failures: 3},
+		},
+		{
+			name: "panic is recovered and restarted", config: workerTestConfig(WORKER_RESTART_ON_FAILURE, 0), fails: 2, panics: true,
+			calls: 3, expected: WorkerStatus{State: WORKER_STATE_STOPPED, Restarts: 2, Failures: 2, Panics: 2},
+		},
+		{
+			name: "never restarts", config: workerTestConfig(WORKER_RESTART_NEVER, 0), fails: 1,
+			calls: 1, expected: WorkerStatus{State: WORKER_STATE_FAILED, Failures: 1},
+		},
+		{
+			name: "max restarts", config: workerTestConfig(WORKER_RESTART_ALWAYS, 2), fails: 10, panics: true,
+			calls: 3, expected: WorkerStatus{State: WORKER_STATE_FAILED, Restarts: 2, Failures: 3, Panics: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			w := newWorkerTest(tt.config, tt.fails, tt.panics, &calls)
+			w.loop(context.Background(), logger)
+
+			if calls != tt.calls {
+				t.Errorf("expected %d calls, got %d", tt.calls, calls)
+			}
+			status := w.status
+			status.Name, status.LastError = "", ""
+			if status != tt.expected {
+				t.Errorf("expected status %+v, got %+v", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestWorkerRestartAlwaysOk(t *testing.T) {
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Worker that returns without error is restarted until the context is canceled.
+	var calls int
+	w := &worker{name: "test", config: workerTestConfig(WORKER_RESTART_ALWAYS, 0), fn: func(ctx context.Context) error {
+		if calls++; calls == 3 {
+			cancel()
+		}
+		return nil
+	}}
+	w.loop(ctx, logger)
+	if calls != 3 || w.status.State != WORKER_STATE_STOPPED || w.status.Restarts != 2 || w.status.Failures != 0 {
+		t.Errorf("unexpected calls %d and status %+v", calls, w.status)
+	}
+}
+
+func TestWorkerBackoffOk(t *testing.T) {
+	logger := setupLogger(&Config{LogLevel: LOG_ERROR})
+	config := WorkerConfig{RestartPolicy: WORKER_RESTART_ON_FAILURE, BackoffMin: 20 * time.Millisecond, BackoffMax: 40 * time.Millisecond}
+
+	// Backoff is doubled up to the max: 20ms + 40ms + 40ms.
+	var calls int
+	w := newWorkerTest(config, 3, false, &calls)
+	start := time.Now()
+	w.loop(context.Background(), logger)
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected backoff about 100ms, took %s", elapsed)
+	}
+
+	// Backoff is interrupted by the context cancellation.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	calls = 0
+	w = newWorkerTest(WorkerConfig{RestartPolicy: WORKER_RESTART_ALWAYS, BackoffMin: time.Hour, BackoffMax: time.Hour}, 10, false, &calls)
+	w.loop(ctx, logger)
+	if calls != 1 || w.status.State != WORKER_STATE_STOPPED {
+		t.Errorf("expected stopped after 1 call, got %d calls and %+v", calls, w.status)
+	}
+}
+
+func TestWorkerConfigDefaultOk(t *testing.T) {
+	app := &App{Config: &Config{}}
+	app.logger = setupLogger(&Config{LogLevel: LOG_ERROR})
+	fn := func(ctx context.Context) error { return nil }
+	app.AddWorker("default", fn)
+	app.AddWorker("no-jitter", fn, WorkerConfig{BackoffMin: time.Second, BackoffMax: time.Millisecond})
+	app.AddWorker("invalid-jitter", fn, WorkerConfig{Jitter: 2})
+
+	workers := app.workerRunner.workers
+	if workers[0].config != defaultWorkerConfig {
+		t.Errorf("expected default config, got %+v", workers[0].config)
+	}
+	if c := workers[1].config; c.Jitter != 0 || c.BackoffMax != time.Minute || c.RestartPolicy != WORKER_RESTART_ON_FAILURE {
+		t.Errorf("unexpected config %+v", c)
+	}
+	if c := workers[2].config; c.Jitter != defaultWorkerConfig.Jitter {
+		t.Errorf("expected default jitter, got %v", c.Jitter)
+	}
+}